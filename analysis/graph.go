@@ -0,0 +1,179 @@
+// Package analysis studies how the tags of an account are used together.
+//
+// NewGraph builds the tag co-occurrence graph of a set of bookmarks: every tag is a node and every pair
+// of tags used on the same bookmark is an edge, weighted by the number of bookmarks they share. The graph
+// can be written as Graphviz DOT or JSON:
+//
+//	bookmarks, err := client.PostsAll(&thumbtack.PostsAllInput{})
+//	graph := analysis.NewGraph(*bookmarks, analysis.WithMinWeight(2))
+//	err = graph.WriteDOT(os.Stdout) // dot -Tsvg
+//
+// Pinboard tags are flat, but separator conventions like lang/go imply a hierarchy. NewHierarchy infers it:
+//
+//	hierarchy := analysis.NewHierarchy(graph.Tags(), analysis.DefaultSeparators)
+//	err = hierarchy.WriteText(os.Stdout)
+package analysis
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// Node is a tag of a graph
+type Node struct {
+	// Tag is the tag
+	Tag string `json:"tag"`
+
+	// Count is the number of bookmarks with the tag
+	Count int `json:"count"`
+}
+
+// Edge is a pair of tags used on the same bookmarks
+type Edge struct {
+	// Source is the first tag of the pair, in case insensitive sort order
+	Source string `json:"source"`
+
+	// Target is the second tag of the pair
+	Target string `json:"target"`
+
+	// Weight is the number of bookmarks with both tags
+	Weight int `json:"weight"`
+}
+
+// Graph is the tag co-occurrence graph
+type Graph struct {
+	// Nodes are the tags, in case insensitive sort order
+	Nodes []Node `json:"nodes"`
+
+	// Edges are the pairs of tags used together, in case insensitive sort order of source and target
+	Edges []Edge `json:"edges"`
+}
+
+// GraphOption configures NewGraph
+type GraphOption func(g *grapher)
+
+// grapher holds the NewGraph options
+type grapher struct {
+	// minCount. the least number of bookmarks of a node
+	minCount int
+
+	// minWeight. the least weight of an edge
+	minWeight int
+}
+
+// WithMinCount leaves out tags used by fewer than count bookmarks, and their edges. Default is 1.
+func WithMinCount(count int) GraphOption {
+	return func(g *grapher) {
+		g.minCount = count
+	}
+}
+
+// WithMinWeight leaves out edges between tags sharing fewer than weight bookmarks. Default is 1.
+func WithMinWeight(weight int) GraphOption {
+	return func(g *grapher) {
+		g.minWeight = weight
+	}
+}
+
+// NewGraph builds the tag co-occurrence graph of bookmarks. Tags are compared case insensitively and
+// spelled as first seen; system tags (via:) are left out.
+func NewGraph(bookmarks []thumbtack.Bookmark, opts ...GraphOption) *Graph {
+	g := &grapher{minCount: 1, minWeight: 1}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	names := map[string]string{}
+	counts := map[string]int{}
+	weights := map[[2]string]int{}
+	for _, bookmark := range bookmarks {
+		tags := []string{}
+		seen := map[string]bool{}
+		for _, tag := range bookmark.Tags {
+			key := strings.ToLower(tag)
+			if tag == "" || thumbtack.Tag(tag).IsSystem() || seen[key] {
+				continue
+			}
+			seen[key] = true
+			if _, ok := names[key]; !ok {
+				names[key] = tag
+			}
+			counts[key]++
+			tags = append(tags, key)
+		}
+
+		sort.Strings(tags)
+		for i, a := range tags {
+			for _, b := range tags[i+1:] {
+				weights[[2]string{a, b}]++
+			}
+		}
+	}
+
+	graph := &Graph{Nodes: []Node{}, Edges: []Edge{}}
+	for key, count := range counts {
+		if count >= g.minCount {
+			graph.Nodes = append(graph.Nodes, Node{Tag: names[key], Count: count})
+		}
+	}
+	for pair, weight := range weights {
+		if weight >= g.minWeight && counts[pair[0]] >= g.minCount && counts[pair[1]] >= g.minCount {
+			graph.Edges = append(graph.Edges, Edge{Source: names[pair[0]], Target: names[pair[1]], Weight: weight})
+		}
+	}
+
+	// tags are unique ignoring case, so sorting on the lower case spellings is a total order
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return strings.ToLower(graph.Nodes[i].Tag) < strings.ToLower(graph.Nodes[j].Tag)
+	})
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if !strings.EqualFold(a.Source, b.Source) {
+			return strings.ToLower(a.Source) < strings.ToLower(b.Source)
+		}
+		return strings.ToLower(a.Target) < strings.ToLower(b.Target)
+	})
+	return graph
+}
+
+// Tags returns the tags of the graph and their counts, like TagsGet
+func (g *Graph) Tags() map[string]int {
+	tags := make(map[string]int, len(g.Nodes))
+	for _, node := range g.Nodes {
+		tags[node.Tag] = node.Count
+	}
+	return tags
+}
+
+// WriteJSON writes the graph to w as JSON
+func (g *Graph) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(g)
+}
+
+// WriteDOT writes the graph to w as an undirected Graphviz graph. Nodes are labelled with their count
+// and edges with their weight.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	buf := bufio.NewWriter(w)
+	fmt.Fprintln(buf, "graph tags {")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(buf, "  %s [label=%s];\n", quoteDOT(node.Tag), quoteDOT(fmt.Sprintf("%s (%d)", node.Tag, node.Count)))
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(buf, "  %s -- %s [weight=%d, label=\"%d\"];\n", quoteDOT(edge.Source), quoteDOT(edge.Target), edge.Weight, edge.Weight)
+	}
+	fmt.Fprintln(buf, "}")
+	return buf.Flush()
+}
+
+// quoteDOT quotes s as a DOT identifier
+func quoteDOT(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,87 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+var bookmarks = []thumbtack.Bookmark{
+	{Href: "https://go.dev", Tags: []string{"lang/go", "programming", "via:popular"}},
+	{Href: "https://pkg.go.dev", Tags: []string{"LANG/GO", "programming", "http"}},
+	{Href: "https://www.rust-lang.org", Tags: []string{"lang/rust", "programming"}},
+	{Href: "https://example.com", Tags: []string{"misc"}},
+}
+
+// TestNewGraph tests building the co-occurrence graph
+func TestNewGraph(t *testing.T) {
+	graph := NewGraph(bookmarks)
+	nodes := []Node{{Tag: "http", Count: 1}, {Tag: "lang/go", Count: 2}, {Tag: "lang/rust", Count: 1}, {Tag: "misc", Count: 1}, {Tag: "programming", Count: 3}}
+	if !reflect.DeepEqual(graph.Nodes, nodes) {
+		t.Errorf("expected nodes %+v, got %+v", nodes, graph.Nodes)
+	}
+	edges := []Edge{
+		{Source: "http", Target: "lang/go", Weight: 1},
+		{Source: "http", Target: "programming", Weight: 1},
+		{Source: "lang/go", Target: "programming", Weight: 2},
+		{Source: "lang/rust", Target: "programming", Weight: 1},
+	}
+	if !reflect.DeepEqual(graph.Edges, edges) {
+		t.Errorf("expected edges %+v, got %+v", edges, graph.Edges)
+	}
+
+	graph = NewGraph(bookmarks, WithMinWeight(2), WithMinCount(2))
+	if len(graph.Nodes) != 2 || len(graph.Edges) != 1 || graph.Edges[0].Weight != 2 {
+		t.Errorf("expected lang/go and programming only, got %+v", graph)
+	}
+}
+
+// TestNewGraphCaseInsensitiveOrder tests that nodes and edges sort ignoring case
+func TestNewGraphCaseInsensitiveOrder(t *testing.T) {
+	graph := NewGraph([]thumbtack.Bookmark{{Href: "https://go.dev", Tags: []string{"Zig", "go", "API"}}})
+	nodes := []Node{{Tag: "API", Count: 1}, {Tag: "go", Count: 1}, {Tag: "Zig", Count: 1}}
+	if !reflect.DeepEqual(graph.Nodes, nodes) {
+		t.Errorf("expected nodes %+v, got %+v", nodes, graph.Nodes)
+	}
+	edges := []Edge{
+		{Source: "API", Target: "go", Weight: 1},
+		{Source: "API", Target: "Zig", Weight: 1},
+		{Source: "go", Target: "Zig", Weight: 1},
+	}
+	if !reflect.DeepEqual(graph.Edges, edges) {
+		t.Errorf("expected edges %+v, got %+v", edges, graph.Edges)
+	}
+}
+
+// TestGraphWrite tests the DOT and JSON exports
+func TestGraphWrite(t *testing.T) {
+	graph := &Graph{
+		Nodes: []Node{{Tag: "go", Count: 2}, {Tag: `say "hi"`, Count: 1}},
+		Edges: []Edge{{Source: "go", Target: `say "hi"`, Weight: 1}},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := graph.WriteDOT(buf); err != nil {
+		t.Fatalf("failed to write DOT: %v", err)
+	}
+	want := "graph tags {\n" +
+		"  \"go\" [label=\"go (2)\"];\n" +
+		"  \"say \\\"hi\\\"\" [label=\"say \\\"hi\\\" (1)\"];\n" +
+		"  \"go\" -- \"say \\\"hi\\\"\" [weight=1, label=\"1\"];\n" +
+		"}\n"
+	if buf.String() != want {
+		t.Errorf("expected DOT\n%s\ngot\n%s", want, buf.String())
+	}
+
+	buf.Reset()
+	if err := graph.WriteJSON(buf); err != nil {
+		t.Fatalf("failed to write JSON: %v", err)
+	}
+	read := &Graph{}
+	if err := json.Unmarshal(buf.Bytes(), read); err != nil || !reflect.DeepEqual(read, graph) {
+		t.Errorf("expected %+v after a round trip, got %+v (%v)", graph, read, err)
+	}
+}
@@ -0,0 +1,110 @@
+package analysis
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DefaultSeparators are the separators of hierarchical tags, e.g. lang/go or lang:go
+const DefaultSeparators = "/:"
+
+// Branch is a level of a tag hierarchy
+type Branch struct {
+	// Name is the name of the level, e.g. go in lang/go
+	Name string `json:"name"`
+
+	// Tag is the tag at this level, e.g. lang/go, or empty if the level is only implied by deeper tags
+	Tag string `json:"tag,omitempty"`
+
+	// Count is the number of bookmarks with Tag
+	Count int `json:"count"`
+
+	// Total is the number of uses of Tag and of all tags below it
+	Total int `json:"total"`
+
+	// Children are the levels below, sorted by name
+	Children []*Branch `json:"children,omitempty"`
+}
+
+// Hierarchy is the tree of tags implied by separators
+type Hierarchy struct {
+	// Roots are the top levels, sorted by name. Tags without a separator are roots without children.
+	Roots []*Branch `json:"roots"`
+}
+
+// NewHierarchy builds the hierarchy of tags, as returned by TagsGet or Graph.Tags, splitting them at
+// any of the runes in separators. Empty levels, e.g. from a leading or doubled separator, are skipped;
+// levels are compared case insensitively and spelled like the tag at that level, if there is one.
+func NewHierarchy(tags map[string]int, separators string) *Hierarchy {
+	root := &Branch{}
+	index := map[*Branch]map[string]*Branch{root: {}}
+
+	names := make([]string, 0, len(tags))
+	for tag := range tags {
+		names = append(names, tag)
+	}
+	sort.Strings(names)
+
+	for _, tag := range names {
+		levels := strings.FieldsFunc(tag, func(r rune) bool { return strings.ContainsRune(separators, r) })
+		if len(levels) == 0 {
+			continue
+		}
+
+		branch := root
+		for _, level := range levels {
+			key := strings.ToLower(level)
+			child, ok := index[branch][key]
+			if !ok {
+				child = &Branch{Name: level}
+				index[branch][key] = child
+				index[child] = map[string]*Branch{}
+				branch.Children = append(branch.Children, child)
+			}
+			child.Total += tags[tag]
+			branch = child
+		}
+		if branch.Tag == "" {
+			// a level that is a tag itself is spelled like the tag
+			branch.Tag = tag
+			branch.Name = levels[len(levels)-1]
+		}
+		branch.Count += tags[tag]
+	}
+
+	sortBranches(root.Children)
+	return &Hierarchy{Roots: root.Children}
+}
+
+// sortBranches sorts branches and their children by name
+func sortBranches(branches []*Branch) {
+	sort.Slice(branches, func(i, j int) bool { return branches[i].Name < branches[j].Name })
+	for _, branch := range branches {
+		sortBranches(branch.Children)
+	}
+}
+
+// WriteJSON writes the hierarchy to w as JSON
+func (h *Hierarchy) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(h)
+}
+
+// WriteText writes the hierarchy to w as an indented tree, each level with its total
+func (h *Hierarchy) WriteText(w io.Writer) error {
+	buf := bufio.NewWriter(w)
+	var write func(branches []*Branch, depth int)
+	write = func(branches []*Branch, depth int) {
+		for _, branch := range branches {
+			fmt.Fprintf(buf, "%s%s (%d)\n", strings.Repeat("  ", depth), branch.Name, branch.Total)
+			write(branch.Children, depth+1)
+		}
+	}
+	write(h.Roots, 0)
+	return buf.Flush()
+}
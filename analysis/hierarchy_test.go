@@ -0,0 +1,35 @@
+package analysis
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestNewHierarchy tests inferring the hierarchy from separators
+func TestNewHierarchy(t *testing.T) {
+	tags := map[string]int{"lang": 1, "lang/go": 3, "Lang:rust": 2, "lang/go/generics": 1, "misc": 4, "/": 1, "tools//git": 1}
+
+	hierarchy := NewHierarchy(tags, DefaultSeparators)
+	if len(hierarchy.Roots) != 3 {
+		t.Fatalf("expected lang, misc and tools at the top, got %+v", hierarchy.Roots)
+	}
+	lang := hierarchy.Roots[0]
+	if lang.Tag != "lang" || lang.Count != 1 || lang.Total != 7 || len(lang.Children) != 2 {
+		t.Errorf("unexpected lang %+v", lang)
+	}
+	if golang := lang.Children[0]; golang.Tag != "lang/go" || golang.Count != 3 || golang.Total != 4 || golang.Children[0].Tag != "lang/go/generics" {
+		t.Errorf("unexpected lang/go %+v", golang)
+	}
+	if tools := hierarchy.Roots[2]; tools.Tag != "" || tools.Total != 1 || tools.Children[0].Tag != "tools//git" {
+		t.Errorf("expected tools to be implied by tools//git, got %+v", tools)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := hierarchy.WriteText(buf); err != nil {
+		t.Fatalf("failed to write text: %v", err)
+	}
+	want := "lang (7)\n  go (4)\n    generics (1)\n  rust (2)\nmisc (4)\ntools (1)\n  git (1)\n"
+	if buf.String() != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, buf.String())
+	}
+}
@@ -0,0 +1,64 @@
+package thumbtack
+
+import "context"
+
+// API is the set of Pinboard API methods provided by Client.
+// Consume API instead of *Client to swap in fakes, or to layer decorators
+// (caching, logging, rate limiting, ...) that embed an API and override some methods.
+type API interface {
+	NotesAPI
+	PostsAPI
+	TagsAPI
+	UserAPI
+}
+
+// NotesAPI is the set of notes methods provided by Client
+type NotesAPI interface {
+	NotesById(id string) (*Note, error)
+	NotesByIdWithContext(ctx context.Context, id string) (*Note, error)
+	NotesList() (*Notes, error)
+	NotesListWithContext(ctx context.Context) (*Notes, error)
+}
+
+// PostsAPI is the set of posts methods provided by Client, including the streaming variants of PostsAll.
+// Fakes can implement PostsAllStream with NewBookmarkIterator.
+type PostsAPI interface {
+	PostsAdd(input *PostsAddInput) (*Result, error)
+	PostsAddWithContext(ctx context.Context, input *PostsAddInput) (*Result, error)
+	PostsAll(input *PostsAllInput) (*[]Bookmark, error)
+	PostsAllWithContext(ctx context.Context, input *PostsAllInput) (*[]Bookmark, error)
+	PostsAllEach(input *PostsAllInput, fn func(Bookmark) error) error
+	PostsAllEachWithContext(ctx context.Context, input *PostsAllInput, fn func(Bookmark) error) error
+	PostsAllStream(ctx context.Context, input *PostsAllInput) (*BookmarkIterator, error)
+	PostsDates(tags []string) (*Dates, error)
+	PostsDatesWithContext(ctx context.Context, tags []string) (*Dates, error)
+	PostsDelete(urlToDelete string) (*Result, error)
+	PostsDeleteWithContext(ctx context.Context, urlToDelete string) (*Result, error)
+	PostsGet(input *PostsGetInput) (*Posts, error)
+	PostsGetWithContext(ctx context.Context, input *PostsGetInput) (*Posts, error)
+	PostsRecent(input *PostsRecentInput) (*Posts, error)
+	PostsRecentWithContext(ctx context.Context, input *PostsRecentInput) (*Posts, error)
+	PostsSuggest(urlToSuggest string) (*Suggestions, error)
+	PostsSuggestWithContext(ctx context.Context, urlToSuggest string) (*Suggestions, error)
+	PostsUpdate() (*UpdateTime, error)
+	PostsUpdateWithContext(ctx context.Context) (*UpdateTime, error)
+}
+
+// TagsAPI is the set of tags methods provided by Client
+type TagsAPI interface {
+	TagsDelete(tag string) (*Result, error)
+	TagsDeleteWithContext(ctx context.Context, tag string) (*Result, error)
+	TagsGet() (*Tags, error)
+	TagsGetWithContext(ctx context.Context) (*Tags, error)
+	TagsRename(input *TagsRenameInput) (*Result, error)
+	TagsRenameWithContext(ctx context.Context, input *TagsRenameInput) (*Result, error)
+}
+
+// UserAPI is the set of user methods provided by Client
+type UserAPI interface {
+	UserSecret() (*Result, error)
+	UserSecretWithContext(ctx context.Context) (*Result, error)
+}
+
+// Client implements API
+var _ API = (*Client)(nil)
@@ -0,0 +1,113 @@
+package thumbtack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// countingAPI is a decorator that counts the calls to TagsGet
+type countingAPI struct {
+	API
+	calls int
+}
+
+func (a *countingAPI) TagsGet() (*Tags, error) {
+	return a.TagsGetWithContext(context.Background())
+}
+
+func (a *countingAPI) TagsGetWithContext(ctx context.Context) (*Tags, error) {
+	a.calls++
+	return a.API.TagsGetWithContext(ctx)
+}
+
+// TestAPIDecorator tests layering a decorator over a Client
+func TestAPIDecorator(t *testing.T) {
+	token := "test:abc123"
+	useragent := "test/1.0"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"books":1,"go":2}`)
+	}))
+	defer ts.Close()
+
+	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(zerolog.PanicLevel)
+	url, _ := url.Parse(ts.URL)
+
+	client, err := New(
+		WithEndpoint(url),
+		WithToken(&token),
+		WithLogger(&log),
+		WithUserAgent(&useragent),
+	)
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+
+	var api API = &countingAPI{API: client}
+	tags, err := api.TagsGet()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tags.Count != 2 {
+		t.Errorf("expected 2 tags, got %d", tags.Count)
+	}
+	if calls := api.(*countingAPI).calls; calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+// TestAPIStream tests streaming bookmarks through the API interface
+func TestAPIStream(t *testing.T) {
+	token := "test:abc123"
+	useragent := "test/1.0"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"href":"https:\/\/example.com\/1","time":"2023-03-20T16:30:35Z"},{"href":"https:\/\/example.com\/2","time":"2023-03-20T16:30:35Z"}]`)
+	}))
+	defer ts.Close()
+
+	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(zerolog.PanicLevel)
+	url, _ := url.Parse(ts.URL)
+
+	client, err := New(
+		WithEndpoint(url),
+		WithToken(&token),
+		WithLogger(&log),
+		WithUserAgent(&useragent),
+	)
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+
+	var api API = &countingAPI{API: client}
+	calls := 0
+	if err := api.PostsAllEach(nil, func(Bookmark) error {
+		calls++
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 bookmarks, got %d", calls)
+	}
+
+	it, err := api.PostsAllStream(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer it.Close()
+	calls = 0
+	for it.Next() {
+		calls++
+	}
+	if it.Err() != nil || calls != 2 {
+		t.Errorf("expected 2 bookmarks and no error, got %d and %v", calls, it.Err())
+	}
+}
@@ -0,0 +1,148 @@
+// Package backup writes a full Pinboard account into a single versioned archive and restores it.
+//
+// An archive holds all bookmarks (with their meta signatures), the tags with their counts,
+// and all notes with their text:
+//
+//	archive, err := backup.Create(ctx, client)
+//	err = archive.Write(file)
+//
+//	archive, err := backup.Read(file)
+//	result, err := backup.Restore(ctx, client, archive, backup.WithJournal("restore.journal"))
+//
+// Pinboard has no api to create notes, so Restore only replays bookmarks.
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// Version is the version of the archive format
+const Version = 1
+
+// ErrUnsupportedVersion is returned by Read when the archive was written in an unknown format version
+type ErrUnsupportedVersion struct {
+	Msg     string
+	Version int
+}
+
+// Error returns the error message
+func (e *ErrUnsupportedVersion) Error() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = "unsupported archive version"
+	}
+	return fmt.Sprintf("%s: %d", msg, e.Version)
+}
+
+// Archive is a backup of an account
+type Archive struct {
+	// Version is the format version, see Version
+	Version int `json:"version"`
+
+	// CreatedAt is the time the backup was made
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdateTime is the update time of the account when the backup was made
+	UpdateTime time.Time `json:"update_time"`
+
+	// Bookmarks are all bookmarks, with meta signatures
+	Bookmarks []thumbtack.Bookmark `json:"bookmarks"`
+
+	// Tags are all tags and the number of times they are used
+	Tags map[string]int `json:"tags"`
+
+	// Notes are all notes, with their text
+	Notes []thumbtack.Note `json:"notes"`
+}
+
+// Create backs up the account of client. Every note is fetched with NotesById to include its text.
+func Create(ctx context.Context, client thumbtack.API) (*Archive, error) {
+	update, err := client.PostsUpdateWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := true
+	bookmarks, err := client.PostsAllWithContext(ctx, &thumbtack.PostsAllInput{Meta: &meta})
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := client.TagsGetWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := client.NotesListWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	notes := make([]thumbtack.Note, 0, len(list.Notes))
+	for _, summary := range list.Notes {
+		note, err := client.NotesByIdWithContext(ctx, summary.Id)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, *note)
+	}
+
+	return &Archive{
+		Version:    Version,
+		CreatedAt:  time.Now().UTC(),
+		UpdateTime: update.UpdateTime,
+		Bookmarks:  *bookmarks,
+		Tags:       tags.Tags,
+		Notes:      notes,
+	}, nil
+}
+
+// Write writes the archive to w as JSON
+func (a *Archive) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(a)
+}
+
+// WriteGzip writes the archive to w as gzip compressed JSON
+func (a *Archive) WriteGzip(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	if err := a.Write(gz); err != nil {
+		gz.Close()
+		return err
+	}
+	return gz.Close()
+}
+
+// Read reads an archive written by Write or WriteGzip from r
+func Read(r io.Reader) (*Archive, error) {
+	buf := bufio.NewReader(r)
+	if magic, err := buf.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buf)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		return decode(gz)
+	}
+	return decode(buf)
+}
+
+// decode decodes an archive and checks its version
+func decode(r io.Reader) (*Archive, error) {
+	archive := &Archive{}
+	if err := json.NewDecoder(r).Decode(archive); err != nil {
+		return nil, err
+	}
+	if archive.Version != Version {
+		return nil, &ErrUnsupportedVersion{Version: archive.Version}
+	}
+	return archive, nil
+}
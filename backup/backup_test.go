@@ -0,0 +1,77 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/thumbtacktest"
+)
+
+// newTestServer returns a fake server and a client for it
+func newTestServer(t *testing.T) (*thumbtacktest.Server, *thumbtack.Client) {
+	srv := thumbtacktest.NewServer("test:abc123")
+	t.Cleanup(srv.Close)
+
+	return srv, srv.QuietClient(t)
+}
+
+// TestCreate tests backing up bookmarks, tags and notes with their text
+func TestCreate(t *testing.T) {
+	srv, client := newTestServer(t)
+	day := time.Date(2023, 3, 20, 16, 30, 35, 0, time.UTC)
+	srv.AddBookmark(thumbtack.Bookmark{Href: "https://example.com", Description: "Example", Tags: []string{"go"}, Time: day, ToRead: true})
+	srv.AddBookmark(thumbtack.Bookmark{Href: "https://example.org", Description: "Org", Tags: []string{"go", "test"}, Time: day.Add(time.Hour), Shared: true})
+	srv.AddNote(thumbtack.Note{Title: "Note", Text: "This is my test note"})
+
+	archive, err := Create(context.Background(), client)
+	if err != nil {
+		t.Fatalf("failed to back up: %v", err)
+	}
+	if archive.Version != Version || !archive.UpdateTime.Equal(srv.UpdateTime()) {
+		t.Errorf("unexpected version or update time %+v", archive)
+	}
+	if len(archive.Bookmarks) != 2 || archive.Bookmarks[0].Meta == "" {
+		t.Errorf("expected 2 bookmarks with meta, got %+v", archive.Bookmarks)
+	}
+	if archive.Tags["go"] != 2 || archive.Tags["test"] != 1 {
+		t.Errorf("unexpected tags %+v", archive.Tags)
+	}
+	if len(archive.Notes) != 1 || archive.Notes[0].Text != "This is my test note" {
+		t.Errorf("expected the note with its text, got %+v", archive.Notes)
+	}
+
+	for _, compress := range []bool{false, true} {
+		buf := &bytes.Buffer{}
+		write := archive.Write
+		if compress {
+			write = archive.WriteGzip
+		}
+		if err := write(buf); err != nil {
+			t.Fatalf("failed to write archive: %v", err)
+		}
+
+		read, err := Read(buf)
+		if err != nil {
+			t.Fatalf("failed to read archive: %v", err)
+		}
+		if len(read.Bookmarks) != 2 || !read.Bookmarks[0].Time.Equal(archive.Bookmarks[0].Time) || read.Bookmarks[0].ToRead != archive.Bookmarks[0].ToRead {
+			t.Errorf("unexpected bookmarks after reading %+v", read.Bookmarks)
+		}
+		if len(read.Notes) != 1 || read.Notes[0].Text != archive.Notes[0].Text {
+			t.Errorf("unexpected notes after reading %+v", read.Notes)
+		}
+	}
+}
+
+// TestReadUnsupportedVersion tests that archives in an unknown format are rejected
+func TestReadUnsupportedVersion(t *testing.T) {
+	unsupported := &ErrUnsupportedVersion{}
+	if _, err := Read(strings.NewReader(`{"version":2}`)); !errors.As(err, &unsupported) || unsupported.Version != 2 {
+		t.Errorf("expected ErrUnsupportedVersion, got %v", err)
+	}
+}
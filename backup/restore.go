@@ -0,0 +1,137 @@
+package backup
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/netscape"
+)
+
+// RestoreOption configures Restore
+type RestoreOption func(r *restorer)
+
+// restorer holds the Restore options
+type restorer struct {
+	// journal. the path of the journal file, if any
+	journal string
+
+	// progress. called after each bookmark
+	progress func(done int, total int)
+
+	// replace. whether existing bookmarks are replaced
+	replace bool
+}
+
+// WithJournal records every restored bookmark in the file at path. A later Restore with the same journal
+// skips the bookmarks already restored, so a failed restore can be resumed.
+func WithJournal(path string) RestoreOption {
+	return func(r *restorer) {
+		r.journal = path
+	}
+}
+
+// WithProgress sets a function called after each bookmark with the number of bookmarks done and the total
+func WithProgress(progress func(done int, total int)) RestoreOption {
+	return func(r *restorer) {
+		r.progress = progress
+	}
+}
+
+// WithReplace sets whether bookmarks already in the account are replaced by the archived ones.
+// Default is true; if false they are left alone.
+func WithReplace(replace bool) RestoreOption {
+	return func(r *restorer) {
+		r.replace = replace
+	}
+}
+
+// RestoreResult counts the bookmarks handled by Restore
+type RestoreResult struct {
+	// Restored is the number of bookmarks added (or replaced)
+	Restored int `json:"restored"`
+
+	// Resumed is the number of bookmarks skipped because the journal lists them as restored
+	Resumed int `json:"resumed"`
+
+	// Skipped is the number of bookmarks already in the account and not replaced
+	Skipped int `json:"skipped"`
+}
+
+// Restore replays the bookmarks of archive into the account with netscape.Import, keeping their time,
+// shared and toread flags. It stops at the first error, returning the result so far along with it.
+func Restore(ctx context.Context, client thumbtack.PostsAPI, archive *Archive, opts ...RestoreOption) (*RestoreResult, error) {
+	r := &restorer{replace: true}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	result := &RestoreResult{}
+
+	done := map[string]bool{}
+	var journal *os.File
+	if r.journal != "" {
+		var err error
+		if done, err = readJournal(r.journal); err != nil {
+			return result, err
+		}
+		if journal, err = os.OpenFile(r.journal, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600); err != nil {
+			return result, err
+		}
+		defer journal.Close()
+	}
+
+	pending := []thumbtack.Bookmark{}
+	for _, bookmark := range archive.Bookmarks {
+		if done[bookmark.Href] {
+			result.Resumed++
+		} else {
+			pending = append(pending, bookmark)
+		}
+	}
+
+	importOpts := []netscape.ImportOption{netscape.WithReplace(r.replace)}
+	if journal != nil {
+		importOpts = append(importOpts, netscape.WithHandled(func(bookmark thumbtack.Bookmark) error {
+			_, err := journal.WriteString(bookmark.Href + "\n")
+			return err
+		}))
+	}
+	if r.progress != nil {
+		if result.Resumed > 0 {
+			r.progress(result.Resumed, len(archive.Bookmarks))
+		}
+		importOpts = append(importOpts, netscape.WithProgress(func(done int, total int) {
+			r.progress(result.Resumed+done, len(archive.Bookmarks))
+		}))
+	}
+
+	imported, err := netscape.Import(ctx, client, pending, importOpts...)
+	result.Restored, result.Skipped = imported.Added, imported.Skipped
+	return result, err
+}
+
+// readJournal returns the urls listed in the journal at path. A missing journal lists none.
+func readJournal(path string) (map[string]bool, error) {
+	done := map[string]bool{}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if href := strings.TrimSpace(scanner.Text()); href != "" {
+			done[href] = true
+		}
+	}
+	return done, scanner.Err()
+}
@@ -0,0 +1,70 @@
+package backup
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// TestRestoreResume tests that a failed restore resumes from its journal
+func TestRestoreResume(t *testing.T) {
+	srv, client := newTestServer(t)
+	day := time.Date(2023, 3, 20, 16, 30, 35, 0, time.UTC)
+	archive := &Archive{
+		Version: Version,
+		Bookmarks: []thumbtack.Bookmark{
+			{Href: "https://example.com/a", Description: "A", Time: day, Shared: false, ToRead: true, Tags: []string{"go"}},
+			{Href: "https://example.com/b", Description: "B", Time: day.Add(time.Hour), Shared: true},
+			{Href: "https://example.com/c", Description: "C", Time: day.Add(2 * time.Hour), Shared: true},
+		},
+	}
+	journal := filepath.Join(t.TempDir(), "restore.journal")
+
+	srv.FailNextMatching("/posts/add", "url", "https://example.com/b", http.StatusInternalServerError)
+	result, err := Restore(context.Background(), client, archive, WithJournal(journal))
+	if err == nil {
+		t.Fatal("expected the restore to fail")
+	}
+	if result.Restored != 1 || len(srv.Bookmarks()) != 1 {
+		t.Fatalf("expected 1 bookmark restored before the failure, got %+v", result)
+	}
+
+	progress := 0
+	result, err = Restore(context.Background(), client, archive, WithJournal(journal), WithProgress(func(done int, total int) {
+		progress = done
+	}))
+	if err != nil {
+		t.Fatalf("failed to resume: %v", err)
+	}
+	if result.Restored != 2 || result.Resumed != 1 || progress != 3 {
+		t.Errorf("unexpected result %+v after %d bookmarks", result, progress)
+	}
+
+	bookmarks := srv.Bookmarks()
+	if len(bookmarks) != 3 {
+		t.Fatalf("expected 3 bookmarks, got %+v", bookmarks)
+	}
+	a := bookmarks[2]
+	if a.Href != "https://example.com/a" || !a.Time.Equal(day) || a.Shared || !a.ToRead || a.Tags[0] != "go" {
+		t.Errorf("unexpected restored bookmark %+v", a)
+	}
+}
+
+// TestRestoreNoReplace tests that existing bookmarks are skipped without replace
+func TestRestoreNoReplace(t *testing.T) {
+	srv, client := newTestServer(t)
+	srv.AddBookmark(thumbtack.Bookmark{Href: "https://example.com", Description: "Existing"})
+	archive := &Archive{Version: Version, Bookmarks: []thumbtack.Bookmark{{Href: "https://example.com", Description: "Archived"}}}
+
+	result, err := Restore(context.Background(), client, archive, WithReplace(false))
+	if err != nil {
+		t.Fatalf("failed to restore: %v", err)
+	}
+	if result.Skipped != 1 || srv.Bookmarks()[0].Description != "Existing" {
+		t.Errorf("expected the existing bookmark to be kept, got %+v", result)
+	}
+}
@@ -0,0 +1,199 @@
+package cassette
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/thumbtacktest"
+	"github.com/rs/zerolog"
+)
+
+// newLogger returns a silent logger
+func newLogger() *zerolog.Logger {
+	log := zerolog.Nop()
+	return &log
+}
+
+// record records a PostsAll and a TagsGet call against a fake server and returns the cassette path
+func record(t *testing.T) string {
+	srv := thumbtacktest.NewServer("test:abc123")
+	t.Cleanup(srv.Close)
+	srv.AddBookmark(thumbtack.Bookmark{
+		Href:        "https://example.com",
+		Description: "Example",
+		Tags:        []string{"go", "test"},
+		Time:        time.Date(2023, 3, 20, 16, 30, 35, 0, time.UTC),
+	})
+
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatalf("failed to create recorder: %v", err)
+	}
+
+	client := srv.QuietClient(t, thumbtack.WithTransport(recorder))
+	if _, err := client.PostsAll(&thumbtack.PostsAllInput{Tags: []string{"go"}}); err != nil {
+		t.Fatalf("failed to record posts/all: %v", err)
+	}
+	if _, err := client.TagsGet(); err != nil {
+		t.Fatalf("failed to record tags/get: %v", err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("failed to close recorder: %v", err)
+	}
+	return path
+}
+
+// newReplayClient returns a client replaying the cassette at path
+func newReplayClient(t *testing.T, path string, opts ...ReplayerOption) (*Replayer, *thumbtack.Client) {
+	replayer, err := Load(path, opts...)
+	if err != nil {
+		t.Fatalf("failed to load cassette: %v", err)
+	}
+
+	token := "other:def456"
+	endpoint, _ := url.Parse("http://127.0.0.1:1")
+	client, err := thumbtack.New(
+		thumbtack.WithLogger(newLogger()),
+		thumbtack.WithToken(&token),
+		thumbtack.WithEndpoint(endpoint),
+		thumbtack.WithTransport(replayer),
+	)
+	if err != nil {
+		t.Fatalf("failed to create thumbtack client: %v", err)
+	}
+	return replayer, client
+}
+
+// TestRecorderScrubsToken tests that the auth token is not written to the cassette
+func TestRecorderScrubsToken(t *testing.T) {
+	path := record(t)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read cassette: %v", err)
+	}
+	if strings.Contains(string(data), "abc123") {
+		t.Errorf("cassette contains the auth token: %s", data)
+	}
+	if !strings.Contains(string(data), "test:"+Redacted) {
+		t.Errorf("expected the scrubbed token in the cassette: %s", data)
+	}
+	if lines := strings.Count(strings.TrimSpace(string(data)), "\n") + 1; lines != 2 {
+		t.Errorf("expected 2 interactions, got %d", lines)
+	}
+}
+
+// TestReplayStrict tests that recorded calls are replayed without a server
+func TestReplayStrict(t *testing.T) {
+	replayer, client := newReplayClient(t, record(t))
+
+	bookmarks, err := client.PostsAll(&thumbtack.PostsAllInput{Tags: []string{"go"}})
+	if err != nil {
+		t.Fatalf("failed to replay posts/all: %v", err)
+	}
+	if len(*bookmarks) != 1 || (*bookmarks)[0].Href != "https://example.com" {
+		t.Errorf("unexpected bookmarks %+v", bookmarks)
+	}
+
+	tags, err := client.TagsGet()
+	if err != nil {
+		t.Fatalf("failed to replay tags/get: %v", err)
+	}
+	if tags.Tags["go"] != 1 {
+		t.Errorf("unexpected tags %+v", tags.Tags)
+	}
+
+	if replayer.Remaining() != 0 {
+		t.Errorf("expected all interactions to be replayed, %d remaining", replayer.Remaining())
+	}
+
+	// every interaction is used only once
+	_, err = client.TagsGet()
+	noInteraction := &ErrNoInteraction{}
+	if !errors.As(err, &noInteraction) {
+		t.Errorf("expected ErrNoInteraction, got %v", err)
+	}
+}
+
+// TestReplayStrictQueryMismatch tests that strict matching compares the query
+func TestReplayStrictQueryMismatch(t *testing.T) {
+	_, client := newReplayClient(t, record(t))
+
+	_, err := client.PostsAll(&thumbtack.PostsAllInput{Tags: []string{"other"}})
+	noInteraction := &ErrNoInteraction{}
+	if !errors.As(err, &noInteraction) {
+		t.Fatalf("expected ErrNoInteraction, got %v", err)
+	}
+	if noInteraction.Path != "/posts/all" || strings.Contains(noInteraction.Query, "def456") {
+		t.Errorf("unexpected error %v", noInteraction)
+	}
+}
+
+// TestReplayLoose tests that loose matching ignores the volatile query parameters only
+func TestReplayLoose(t *testing.T) {
+	_, client := newReplayClient(t, record(t), WithMatching(MatchLoose))
+
+	from := time.Now().Add(-time.Hour)
+	bookmarks, err := client.PostsAll(&thumbtack.PostsAllInput{Tags: []string{"go"}, FromDT: &from})
+	if err != nil {
+		t.Fatalf("failed to replay posts/all: %v", err)
+	}
+	if len(*bookmarks) != 1 {
+		t.Errorf("expected the recorded bookmark, got %+v", bookmarks)
+	}
+
+	_, client = newReplayClient(t, record(t), WithMatching(MatchLoose))
+	_, err = client.PostsAll(&thumbtack.PostsAllInput{Tags: []string{"other"}})
+	noInteraction := &ErrNoInteraction{}
+	if !errors.As(err, &noInteraction) {
+		t.Errorf("expected ErrNoInteraction for a different tag, got %v", err)
+	}
+}
+
+// TestReplayLooseSamePath tests that different queries on the same path replay their own recordings
+func TestReplayLooseSamePath(t *testing.T) {
+	srv := thumbtacktest.NewServer("test:abc123")
+	t.Cleanup(srv.Close)
+	srv.AddBookmark(thumbtack.Bookmark{Href: "https://example.com", Description: "Example", Tags: []string{"go"}})
+
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatalf("failed to create recorder: %v", err)
+	}
+	client := srv.QuietClient(t, thumbtack.WithTransport(recorder))
+	for _, tag := range []string{"go", "rust"} {
+		if _, err := client.PostsAll(&thumbtack.PostsAllInput{Tags: []string{tag}}); err != nil {
+			t.Fatalf("failed to record posts/all: %v", err)
+		}
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("failed to close recorder: %v", err)
+	}
+
+	_, client = newReplayClient(t, path, WithMatching(MatchLoose))
+	for tag, want := range map[string]int{"rust": 0, "go": 1} {
+		bookmarks, err := client.PostsAll(&thumbtack.PostsAllInput{Tags: []string{tag}})
+		if err != nil {
+			t.Fatalf("failed to replay posts/all for %s: %v", tag, err)
+		}
+		if len(*bookmarks) != want {
+			t.Errorf("expected %d bookmarks for %s, got %d", want, tag, len(*bookmarks))
+		}
+	}
+}
+
+// TestReadBadCassette tests that a malformed cassette is rejected
+func TestReadBadCassette(t *testing.T) {
+	_, err := Read(strings.NewReader("{\"request\":{}}\n\nnot json\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("expected an error for line 3, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// RecorderOption configures a Recorder
+type RecorderOption func(r *Recorder)
+
+// Recorder is an http.RoundTripper that passes requests on to another RoundTripper
+// and appends every interaction to a cassette file. It is safe for concurrent use.
+type Recorder struct {
+	// encoder. writes interactions to file
+	encoder *json.Encoder
+
+	// file. the cassette file
+	file *os.File
+
+	// mu. serializes writes to file
+	mu sync.Mutex
+
+	// next. the RoundTripper making the real requests
+	next http.RoundTripper
+}
+
+// NewRecorder creates (or truncates) the cassette file at path and returns a Recorder writing to it.
+// Call Close when done.
+func NewRecorder(path string, opts ...RecorderOption) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Recorder{
+		encoder: json.NewEncoder(file),
+		file:    file,
+		next:    http.DefaultTransport,
+	}
+	r.encoder.SetEscapeHTML(false)
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r, nil
+}
+
+// WithNext sets the RoundTripper making the real requests. Default is http.DefaultTransport.
+func WithNext(next http.RoundTripper) RecorderOption {
+	return func(r *Recorder) {
+		r.next = next
+	}
+}
+
+// RoundTrip makes the request with the next RoundTripper and records the interaction.
+// Transport errors are returned as is and not recorded.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  scrubQuery(req.URL.Query()),
+		},
+		Response: Response{
+			StatusCode: res.StatusCode,
+			Status:     res.Status,
+			Header:     res.Header.Clone(),
+			Body:       string(body),
+		},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.encoder.Encode(interaction); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Close closes the cassette file
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
@@ -0,0 +1,162 @@
+package cassette
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrNoInteraction is returned by a Replayer when no recorded interaction matches a request
+type ErrNoInteraction struct {
+	Method string
+	Msg    string
+	Path   string
+	Query  string
+}
+
+// Error returns the error message
+func (e *ErrNoInteraction) Error() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = "no recorded interaction matches request"
+	}
+	if e.Method != "" || e.Path != "" {
+		msg += fmt.Sprintf(": %s %s", e.Method, e.Path)
+	}
+	if e.Query != "" {
+		msg += "?" + e.Query
+	}
+	return msg
+}
+
+// ReplayerOption configures a Replayer
+type ReplayerOption func(p *Replayer)
+
+// Replayer is an http.RoundTripper that answers requests from recorded interactions
+// without making any network calls. Each interaction is used once, in recorded order.
+// It is safe for concurrent use.
+type Replayer struct {
+	// interactions. the recorded interactions
+	interactions []Interaction
+
+	// mode. how requests are matched to interactions
+	mode MatchMode
+
+	// mu. guards used
+	mu sync.Mutex
+
+	// used. whether each interaction was replayed
+	used []bool
+}
+
+// Load reads the cassette file at path and returns a Replayer for it
+func Load(path string, opts ...ReplayerOption) (*Replayer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Read(file, opts...)
+}
+
+// Read reads a cassette from r and returns a Replayer for it
+func Read(r io.Reader, opts ...ReplayerOption) (*Replayer, error) {
+	p := &Replayer{mode: MatchStrict}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		interaction := Interaction{}
+		if err := json.Unmarshal(scanner.Bytes(), &interaction); err != nil {
+			return nil, fmt.Errorf("cassette line %d: %w", line, err)
+		}
+		p.interactions = append(p.interactions, interaction)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	p.used = make([]bool, len(p.interactions))
+	return p, nil
+}
+
+// WithMatching sets how requests are matched to interactions. Default is MatchStrict.
+func WithMatching(mode MatchMode) ReplayerOption {
+	return func(p *Replayer) {
+		p.mode = mode
+	}
+}
+
+// RoundTrip returns the response of the first unused interaction matching req
+func (p *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	query := req.URL.Query()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, interaction := range p.interactions {
+		if p.used[i] || !interaction.Request.matches(p.mode, req.Method, req.URL.Path, query) {
+			continue
+		}
+		p.used[i] = true
+
+		header := http.Header{}
+		for key, values := range interaction.Response.Header {
+			header[key] = append([]string{}, values...)
+		}
+		status := interaction.Response.Status
+		if status == "" {
+			status = fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode))
+		}
+
+		return &http.Response{
+			Status:        status,
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader([]byte(interaction.Response.Body))),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, &ErrNoInteraction{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  scrubQuery(query).Encode(),
+	}
+}
+
+// Remaining returns the number of recorded interactions that were not replayed
+func (p *Replayer) Remaining() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	remaining := 0
+	for _, used := range p.used {
+		if !used {
+			remaining++
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,93 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/backup"
+	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/clictx"
+)
+
+// BackupCmd is the command to back up the account into an archive file
+type BackupCmd struct {
+	File string `arg:"" name:"file" help:"Archive file to write (gzip compressed if it ends in .gz)" type:"path"`
+}
+
+// Run runs the command
+func (cmd *BackupCmd) Run(ctx *clictx.Context) error {
+	// Say hello
+	ctx.Log.Debug().
+		Str("cmd", "backup").
+		Str("app_name", ctx.Appname).
+		Msg("Running command")
+
+	// Create thumbtack client
+	client, err := thumbtack.New(
+		thumbtack.WithEndpoint(ctx.Endpoint),
+		thumbtack.WithToken(ctx.Token),
+		thumbtack.WithLogger(ctx.Log),
+		thumbtack.WithUserAgent(ctx.UserAgent),
+		thumbtack.WithRateLimiter(thumbtack.NewRateLimiter()),
+	)
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "backup").
+			Str("app_name", ctx.Appname).
+			Msg("Failed to create client")
+		return err
+	}
+
+	// Back up the account
+	archive, err := backup.Create(context.Background(), client)
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "backup").
+			Str("app_name", ctx.Appname).
+			Msg("Failed to back up account")
+		return err
+	}
+
+	// Write the archive next to the destination, then move it into place
+	file, err := os.CreateTemp(filepath.Dir(cmd.File), ".thumbtack-backup-*")
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "backup").
+			Str("app_name", ctx.Appname).
+			Str("file", cmd.File).
+			Msg("Failed to create archive file")
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if strings.HasSuffix(cmd.File, ".gz") {
+		err = archive.WriteGzip(file)
+	} else {
+		err = archive.Write(file)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), cmd.File)
+	}
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "backup").
+			Str("app_name", ctx.Appname).
+			Str("file", cmd.File).
+			Msg("Failed to write archive")
+		return err
+	}
+
+	ctx.Log.Info().
+		Str("cmd", "backup").
+		Str("file", cmd.File).
+		Int("bookmarks", len(archive.Bookmarks)).
+		Int("tags", len(archive.Tags)).
+		Int("notes", len(archive.Notes)).
+		Msg("Backed up account")
+	return nil
+}
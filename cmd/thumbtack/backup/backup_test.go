@@ -0,0 +1,7 @@
+package backup
+
+import "testing"
+
+func TestSkip(t *testing.T) {
+	t.Skip("This module provides a reference CLI for the Thumbtack package.")
+}
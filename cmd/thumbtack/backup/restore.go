@@ -0,0 +1,101 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/backup"
+	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/clictx"
+)
+
+// RestoreCmd is the command to restore the bookmarks of an archive file into the account
+type RestoreCmd struct {
+	File    string  `arg:"" name:"file" help:"Archive file to restore" type:"existingfile"`
+	Journal *string `name:"journal" help:"Journal of restored bookmarks, to resume a failed restore (default: <file>.journal)" type:"string"`
+	Replace bool    `name:"replace" negatable:"" help:"Replace bookmarks already in the account" default:"true" type:"bool"`
+}
+
+// Run runs the command
+func (cmd *RestoreCmd) Run(ctx *clictx.Context) error {
+	// Say hello
+	ctx.Log.Debug().
+		Str("cmd", "restore").
+		Str("app_name", ctx.Appname).
+		Msg("Running command")
+
+	file, err := os.Open(cmd.File)
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "restore").
+			Str("app_name", ctx.Appname).
+			Str("file", cmd.File).
+			Msg("Failed to open archive")
+		return err
+	}
+	defer file.Close()
+
+	archive, err := backup.Read(file)
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "restore").
+			Str("app_name", ctx.Appname).
+			Str("file", cmd.File).
+			Msg("Failed to read archive")
+		return err
+	}
+
+	// Create thumbtack client
+	client, err := thumbtack.New(
+		thumbtack.WithEndpoint(ctx.Endpoint),
+		thumbtack.WithToken(ctx.Token),
+		thumbtack.WithLogger(ctx.Log),
+		thumbtack.WithUserAgent(ctx.UserAgent),
+		thumbtack.WithRateLimiter(thumbtack.NewRateLimiter()),
+	)
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "restore").
+			Str("app_name", ctx.Appname).
+			Msg("Failed to create client")
+		return err
+	}
+
+	journal := cmd.File + ".journal"
+	if cmd.Journal != nil {
+		journal = *cmd.Journal
+	}
+
+	// Replay the bookmarks
+	result, err := backup.Restore(context.Background(), client, archive,
+		backup.WithJournal(journal),
+		backup.WithReplace(cmd.Replace),
+		backup.WithProgress(func(done int, total int) {
+			fmt.Fprintf(os.Stderr, "\rrestored %d/%d", done, total)
+		}),
+	)
+	if len(archive.Bookmarks) > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "restore").
+			Str("app_name", ctx.Appname).
+			Str("journal", journal).
+			Int("restored", result.Restored).
+			Msg("Failed to restore bookmarks, run again to resume")
+		return err
+	}
+
+	// The restore is complete, so the journal is no longer needed
+	if err := os.Remove(journal); err != nil && !os.IsNotExist(err) {
+		ctx.Log.Warn().
+			Str("cmd", "restore").
+			Str("journal", journal).
+			Msg("Failed to remove journal")
+	}
+
+	fmt.Printf("restored: %d, resumed: %d, skipped: %d\n", result.Restored, result.Resumed, result.Skipped)
+	return nil
+}
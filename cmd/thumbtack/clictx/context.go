@@ -17,6 +17,12 @@ type Context struct {
 	// log is the logger
 	Log *zerolog.Logger
 
+	// MirrorDir is the directory of the local mirror
+	MirrorDir string
+
+	// RulesFile is the auto-tagging rules file, or empty if there is none
+	RulesFile string
+
 	// Token is the token to use
 	Token *string
 
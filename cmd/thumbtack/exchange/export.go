@@ -0,0 +1,113 @@
+package exchange
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/clictx"
+	"github.com/rmrfslashbin/thumbtack/mirror"
+	"github.com/rmrfslashbin/thumbtack/netscape"
+)
+
+// ExportCmd is the command to export all bookmarks to a file
+type ExportCmd struct {
+	Format  string  `name:"format" help:"Format of the export" default:"netscape" enum:"netscape,json"`
+	Output  *string `name:"output" short:"o" help:"Write to this file instead of stdout" type:"string"`
+	Offline bool    `name:"offline" help:"Export the local mirror (see 'thumbtack sync')" default:"false" type:"bool"`
+}
+
+// Run runs the command
+func (cmd *ExportCmd) Run(ctx *clictx.Context) error {
+	// Say hello
+	ctx.Log.Debug().
+		Str("cmd", "export").
+		Str("app_name", ctx.Appname).
+		Str("format", cmd.Format).
+		Msg("Running command")
+
+	var bookmarks []thumbtack.Bookmark
+	if cmd.Offline {
+		// Export the local mirror
+		snapshot, err := mirror.Load(ctx.MirrorDir)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "export").
+				Str("app_name", ctx.Appname).
+				Str("mirror_dir", ctx.MirrorDir).
+				Msg("Failed to load local mirror")
+			return err
+		}
+		bookmarks = snapshot.Bookmarks
+	} else {
+		// Create thumbtack client
+		client, err := thumbtack.New(
+			thumbtack.WithEndpoint(ctx.Endpoint),
+			thumbtack.WithToken(ctx.Token),
+			thumbtack.WithLogger(ctx.Log),
+			thumbtack.WithUserAgent(ctx.UserAgent),
+		)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "export").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to create client")
+			return err
+		}
+
+		// Get all bookmarks
+		all, err := client.PostsAll(&thumbtack.PostsAllInput{})
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "export").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to get bookmarks")
+			return err
+		}
+		bookmarks = *all
+	}
+
+	var err error
+	var w io.Writer = os.Stdout
+	var file *os.File
+	if cmd.Output != nil {
+		file, err = os.Create(*cmd.Output)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "export").
+				Str("app_name", ctx.Appname).
+				Str("output", *cmd.Output).
+				Msg("Failed to create output file")
+			return err
+		}
+		w = file
+	}
+
+	switch cmd.Format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		err = encoder.Encode(bookmarks)
+	default:
+		err = netscape.Encode(w, bookmarks)
+	}
+	if file != nil {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "export").
+			Str("app_name", ctx.Appname).
+			Msg("Failed to write bookmarks")
+		return err
+	}
+
+	ctx.Log.Info().
+		Str("cmd", "export").
+		Int("bookmarks", len(bookmarks)).
+		Msg("Exported bookmarks")
+	return nil
+}
@@ -0,0 +1,96 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/clictx"
+	"github.com/rmrfslashbin/thumbtack/netscape"
+)
+
+// ImportCmd is the command to import bookmarks from a file
+type ImportCmd struct {
+	File    string `arg:"" name:"file" help:"File to import" type:"existingfile"`
+	Format  string `name:"format" help:"Format of the file" default:"netscape" enum:"netscape,json"`
+	Replace bool   `name:"replace" negatable:"" help:"Replace bookmarks already in the account" default:"false" type:"bool"`
+}
+
+// Run runs the command
+func (cmd *ImportCmd) Run(ctx *clictx.Context) error {
+	// Say hello
+	ctx.Log.Debug().
+		Str("cmd", "import").
+		Str("app_name", ctx.Appname).
+		Str("format", cmd.Format).
+		Msg("Running command")
+
+	file, err := os.Open(cmd.File)
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "import").
+			Str("app_name", ctx.Appname).
+			Str("file", cmd.File).
+			Msg("Failed to open file")
+		return err
+	}
+	defer file.Close()
+
+	// Read the bookmarks
+	var bookmarks []thumbtack.Bookmark
+	switch cmd.Format {
+	case "json":
+		err = json.NewDecoder(file).Decode(&bookmarks)
+	default:
+		bookmarks, err = netscape.Decode(file)
+	}
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "import").
+			Str("app_name", ctx.Appname).
+			Str("file", cmd.File).
+			Msg("Failed to read bookmarks")
+		return err
+	}
+
+	// Create thumbtack client
+	client, err := thumbtack.New(
+		thumbtack.WithEndpoint(ctx.Endpoint),
+		thumbtack.WithToken(ctx.Token),
+		thumbtack.WithLogger(ctx.Log),
+		thumbtack.WithUserAgent(ctx.UserAgent),
+		thumbtack.WithRateLimiter(thumbtack.NewRateLimiter()),
+	)
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "import").
+			Str("app_name", ctx.Appname).
+			Msg("Failed to create client")
+		return err
+	}
+
+	// Add the bookmarks
+	result, err := netscape.Import(context.Background(), client, bookmarks,
+		netscape.WithReplace(cmd.Replace),
+		netscape.WithProgress(func(done int, total int) {
+			fmt.Fprintf(os.Stderr, "\rimported %d/%d", done, total)
+		}),
+	)
+	if len(bookmarks) > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "import").
+			Str("app_name", ctx.Appname).
+			Int("added", result.Added).
+			Int("skipped", result.Skipped).
+			Msg("Failed to import bookmarks")
+		return err
+	}
+
+	fmt.Printf("added: %d, skipped: %d\n", result.Added, result.Skipped)
+	return nil
+}
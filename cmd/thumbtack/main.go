@@ -3,6 +3,8 @@ package main
 import (
 	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/alecthomas/kong"
 	"github.com/rmrfslashbin/thumbtack"
@@ -69,10 +71,39 @@ func main() {
 		userAgent = config.GetUserAgent()
 	}
 
+	// Default to a mirror per account in the user's cache directory
+	var mirrorDir string
+	if cli.MirrorDir != nil {
+		mirrorDir = *cli.MirrorDir
+	} else {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			cacheDir = os.TempDir()
+		}
+		user, _, _ := strings.Cut(cli.Token, ":")
+		mirrorDir = filepath.Join(cacheDir, APP_NAME, user)
+	}
+
+	// Default to a rules file in the user's config directory, if there is one
+	var rulesFile string
+	if cli.Rules != nil {
+		rulesFile = *cli.Rules
+	} else if configDir, err := os.UserConfigDir(); err == nil {
+		for _, name := range []string{"rules.yaml", "rules.yml", "rules.json"} {
+			candidate := filepath.Join(configDir, APP_NAME, name)
+			if _, err := os.Stat(candidate); err == nil {
+				rulesFile = candidate
+				break
+			}
+		}
+	}
+
 	// Call the Run() method of the selected parsed command.
 	err = ctx.Run(
 		&clictx.Context{
 			Log:       &log,
+			MirrorDir: mirrorDir,
+			RulesFile: rulesFile,
 			Token:     &cli.Token,
 			Endpoint:  endpoint,
 			Appname:   APP_NAME,
@@ -3,10 +3,12 @@ package posts
 type PostsCmd struct {
 	Add     PostsAddCmd     `cmd:"" help:"Add a bookmark."`
 	All     PostsAllCmd     `cmd:"" help:"Get all bookmarks."`
+	Autotag PostsAutotagCmd `cmd:"" help:"Apply the auto-tagging rules to all bookmarks."`
 	Dates   PostsDatesCmd   `cmd:"" help:"Get dates with bookmarks."`
 	Del     PostsDeleteCmd  `cmd:"" help:"Delete a bookmark."`
 	Get     PostsGetCmd     `cmd:"" help:"Get specific bookmarks."`
 	Recent  PostsRecentCmd  `cmd:"" help:"Get recent bookmarks."`
+	Search  PostsSearchCmd  `cmd:"" help:"Search bookmarks with a query."`
 	Suggest PostsSuggestCmd `cmd:"" help:"Get suggested tags for a URL."`
 	Update  PostsUpdateCmd  `cmd:"" help:"Returns the most recent time a bookmark was added, updated or deleted."`
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/rmrfslashbin/thumbtack"
 	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/clictx"
+	"github.com/rmrfslashbin/thumbtack/rules"
 )
 
 // PostsAddCmd is the command to add a bookmark.
@@ -21,6 +22,7 @@ type PostsAddCmd struct {
 	Timestamp *time.Time `name:"timestamp" help:"Timestamp to add bookmark(format: 2006-01-02T15:04:05Z)" type:"date"`
 	Json      bool       `name:"json" help:"Output as JSON" default:"false" type:"bool"`
 	Unread    *bool      `name:"unread" help:"Mark bookmark as unread" default:"false" type:"bool"`
+	NoRules   bool       `name:"no-rules" help:"Do not apply the auto-tagging rules file" default:"false" type:"bool"`
 }
 
 // Run runs the command
@@ -46,19 +48,38 @@ func (cmd *PostsAddCmd) Run(ctx *clictx.Context) error {
 		return err
 	}
 
+	input := &thumbtack.PostsAddInput{
+		Url:         &cmd.Url,
+		Title:       &cmd.Title,
+		Description: cmd.Descr,
+		Replace:     &cmd.Replace,
+		Shared:      &cmd.Shared,
+		Tags:        cmd.Tags,
+		Timestamp:   cmd.Timestamp,
+		ToRead:      cmd.Unread,
+	}
+
+	// Apply the auto-tagging rules, if configured
+	if ctx.RulesFile != "" && !cmd.NoRules {
+		set, err := rules.Load(ctx.RulesFile)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "posts add").
+				Str("app_name", ctx.Appname).
+				Str("rules_file", ctx.RulesFile).
+				Msg("Failed to load rules")
+			return err
+		}
+		applied := set.Apply(input)
+		ctx.Log.Debug().
+			Str("cmd", "posts add").
+			Strs("rules", applied).
+			Strs("tags", input.Tags).
+			Msg("Applied rules")
+	}
+
 	// Add bookmark with params
-	add, err := client.PostsAdd(
-		&thumbtack.PostsAddInput{
-			Url:         &cmd.Url,
-			Title:       &cmd.Title,
-			Description: cmd.Descr,
-			Replace:     &cmd.Replace,
-			Shared:      &cmd.Shared,
-			Tags:        cmd.Tags,
-			Timestamp:   cmd.Timestamp,
-			ToRead:      cmd.Unread,
-		},
-	)
+	add, err := client.PostsAdd(input)
 	if err != nil {
 		if _, ok := err.(*thumbtack.ErrUnexpectedResponse); ok {
 			ctx.Log.Error().
@@ -8,6 +8,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/rmrfslashbin/thumbtack"
 	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/clictx"
+	"github.com/rmrfslashbin/thumbtack/mirror"
 )
 
 // PostsAllCmd is the command to get all posts
@@ -19,6 +20,7 @@ type PostsAllCmd struct {
 	Tags    []string   `name:"tags" help:"Get posts with these tags" type:"strings"`
 	To      *time.Time `name:"to" help:"Get posts to this date/time (format: 2006-01-02T15:04:05Z)" type:"date"`
 	Json    bool       `name:"json" help:"Output as JSON" default:"false" type:"bool"`
+	Offline bool       `name:"offline" help:"Answer from the local mirror (see 'thumbtack sync')" default:"false" type:"bool"`
 }
 
 // Run runs the command
@@ -29,38 +31,54 @@ func (cmd *PostsAllCmd) Run(ctx *clictx.Context) error {
 		Str("app_name", ctx.Appname).
 		Msg("Running command")
 
-	// Create thumbtack client
-	client, err := thumbtack.New(
-		thumbtack.WithEndpoint(ctx.Endpoint),
-		thumbtack.WithToken(ctx.Token),
-		thumbtack.WithLogger(ctx.Log),
-		thumbtack.WithUserAgent(ctx.UserAgent),
-	)
-	if err != nil {
-		ctx.Log.Error().
-			Str("cmd", "posts all").
-			Str("app_name", ctx.Appname).
-			Msg("Failed to create client")
-		return err
+	input := &thumbtack.PostsAllInput{
+		FromDT:  cmd.From,
+		ToDT:    cmd.To,
+		Meta:    &cmd.Meta,
+		Results: cmd.Results,
+		Start:   cmd.Start,
+		Tags:    cmd.Tags,
 	}
 
-	// Get bookmarks with params
-	bookmarks, err := client.PostsAll(
-		&thumbtack.PostsAllInput{
-			FromDT:  cmd.From,
-			ToDT:    cmd.To,
-			Meta:    &cmd.Meta,
-			Results: cmd.Results,
-			Start:   cmd.Start,
-			Tags:    cmd.Tags,
-		},
-	)
-	if err != nil {
-		ctx.Log.Error().
-			Str("cmd", "posts all").
-			Str("app_name", ctx.Appname).
-			Msg("Failed to get bookmarks")
-		return err
+	var bookmarks *[]thumbtack.Bookmark
+	if cmd.Offline {
+		// Answer from the local mirror
+		snapshot, err := mirror.Load(ctx.MirrorDir)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "posts all").
+				Str("app_name", ctx.Appname).
+				Str("mirror_dir", ctx.MirrorDir).
+				Msg("Failed to load local mirror")
+			return err
+		}
+		result := snapshot.PostsAll(input)
+		bookmarks = &result
+	} else {
+		// Create thumbtack client
+		client, err := thumbtack.New(
+			thumbtack.WithEndpoint(ctx.Endpoint),
+			thumbtack.WithToken(ctx.Token),
+			thumbtack.WithLogger(ctx.Log),
+			thumbtack.WithUserAgent(ctx.UserAgent),
+		)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "posts all").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to create client")
+			return err
+		}
+
+		// Get bookmarks with params
+		bookmarks, err = client.PostsAll(input)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "posts all").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to get bookmarks")
+			return err
+		}
 	}
 
 	if cmd.Json {
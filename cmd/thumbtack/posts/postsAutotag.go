@@ -0,0 +1,126 @@
+package posts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/clictx"
+	"github.com/rmrfslashbin/thumbtack/rules"
+)
+
+// PostsAutotagCmd is the command to apply the auto-tagging rules to all bookmarks
+type PostsAutotagCmd struct {
+	Apply bool `name:"apply" help:"Update the bookmarks; without it only the changes are shown" default:"false" type:"bool"`
+	Json  bool `name:"json" help:"Output as JSON" default:"false" type:"bool"`
+}
+
+// Run runs the command
+func (cmd *PostsAutotagCmd) Run(ctx *clictx.Context) error {
+	// Say hello
+	ctx.Log.Debug().
+		Str("cmd", "posts autotag").
+		Str("app_name", ctx.Appname).
+		Msg("Running command")
+
+	if ctx.RulesFile == "" {
+		ctx.Log.Error().
+			Str("cmd", "posts autotag").
+			Str("app_name", ctx.Appname).
+			Msg("No rules file configured")
+		return errors.New("no rules file, set --rules or RULES_FILE")
+	}
+	set, err := rules.Load(ctx.RulesFile)
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "posts autotag").
+			Str("app_name", ctx.Appname).
+			Str("rules_file", ctx.RulesFile).
+			Msg("Failed to load rules")
+		return err
+	}
+
+	// Create thumbtack client
+	client, err := thumbtack.New(
+		thumbtack.WithEndpoint(ctx.Endpoint),
+		thumbtack.WithToken(ctx.Token),
+		thumbtack.WithLogger(ctx.Log),
+		thumbtack.WithUserAgent(ctx.UserAgent),
+		thumbtack.WithRateLimiter(thumbtack.NewRateLimiter()),
+	)
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "posts autotag").
+			Str("app_name", ctx.Appname).
+			Msg("Failed to create client")
+		return err
+	}
+
+	bookmarks, err := client.PostsAll(&thumbtack.PostsAllInput{})
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "posts autotag").
+			Str("app_name", ctx.Appname).
+			Msg("Failed to get bookmarks")
+		return err
+	}
+	changes := set.Changes(*bookmarks)
+
+	if !cmd.Apply {
+		// Dry run: show the changes only
+		if cmd.Json {
+			data, err := json.Marshal(changes)
+			if err != nil {
+				ctx.Log.Error().
+					Str("cmd", "posts autotag").
+					Str("app_name", ctx.Appname).
+					Msg("Failed to marshal changes")
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+		for _, change := range changes {
+			fmt.Println(change.Diff())
+		}
+		fmt.Printf("%d of %d bookmarks would change; use --apply to update them\n", len(changes), len(*bookmarks))
+		return nil
+	}
+
+	// Update the bookmarks
+	result, err := rules.Backfill(context.Background(), client, changes,
+		rules.WithProgress(func(done int, total int) {
+			fmt.Fprintf(os.Stderr, "\rupdated %d/%d", done, total)
+		}),
+	)
+	if len(changes) > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "posts autotag").
+			Str("app_name", ctx.Appname).
+			Int("updated", result.Updated).
+			Int("skipped", result.Skipped).
+			Msg("Failed to update bookmarks")
+		return err
+	}
+
+	if cmd.Json {
+		data, err := json.Marshal(result)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "posts autotag").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to marshal result")
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Printf("updated: %d, skipped: %d\n", result.Updated, result.Skipped)
+	}
+	return nil
+}
@@ -8,15 +8,17 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/rmrfslashbin/thumbtack"
 	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/clictx"
+	"github.com/rmrfslashbin/thumbtack/mirror"
 )
 
 // PostsGetCmd is the command to return one or more posts on a single day matching the arguments
 type PostsGetCmd struct {
-	Date *string  `name:"date" help:"Get posts from this date (format: 2006-01-02)" type:"string"`
-	Meta bool     `name:"meta" negatable:"" help:"Get meta data for posts" default:"true" type:"bool"`
-	Tags []string `name:"tag" help:"Get posts with these tags (max: 3)" type:"strings"`
-	Url  *string  `name:"url" help:"Get posts with this URL" type:"string"`
-	Json bool     `name:"json" help:"Output as JSON" default:"false" type:"bool"`
+	Date    *string  `name:"date" help:"Get posts from this date (format: 2006-01-02)" type:"string"`
+	Meta    bool     `name:"meta" negatable:"" help:"Get meta data for posts" default:"true" type:"bool"`
+	Tags    []string `name:"tag" help:"Get posts with these tags (max: 3)" type:"strings"`
+	Url     *string  `name:"url" help:"Get posts with this URL" type:"string"`
+	Json    bool     `name:"json" help:"Output as JSON" default:"false" type:"bool"`
+	Offline bool     `name:"offline" help:"Answer from the local mirror (see 'thumbtack sync')" default:"false" type:"bool"`
 }
 
 // Run runs the command
@@ -27,23 +29,9 @@ func (cmd *PostsGetCmd) Run(ctx *clictx.Context) error {
 		Str("app_name", ctx.Appname).
 		Msg("Running command")
 
-	// Create thumbtack client
-	client, err := thumbtack.New(
-		thumbtack.WithEndpoint(ctx.Endpoint),
-		thumbtack.WithToken(ctx.Token),
-		thumbtack.WithLogger(ctx.Log),
-		thumbtack.WithUserAgent(ctx.UserAgent),
-	)
-	if err != nil {
-		ctx.Log.Error().
-			Str("cmd", "posts get").
-			Str("app_name", ctx.Appname).
-			Msg("Failed to create client")
-		return err
-	}
-
 	var date time.Time
 	if cmd.Date != nil {
+		var err error
 		date, err = time.Parse(time.DateOnly, *cmd.Date)
 		if err != nil {
 			ctx.Log.Error().
@@ -53,21 +41,52 @@ func (cmd *PostsGetCmd) Run(ctx *clictx.Context) error {
 			return err
 		}
 	}
-	// Get bookmarks with params
-	bookmarks, err := client.PostsGet(
-		&thumbtack.PostsGetInput{
-			Date: &date,
-			Meta: &cmd.Meta,
-			Tags: cmd.Tags,
-			URL:  cmd.Url,
-		},
-	)
-	if err != nil {
-		ctx.Log.Error().
-			Str("cmd", "posts get").
-			Str("app_name", ctx.Appname).
-			Msg("Failed to get bookmarks")
-		return err
+
+	input := &thumbtack.PostsGetInput{
+		Date: &date,
+		Meta: &cmd.Meta,
+		Tags: cmd.Tags,
+		URL:  cmd.Url,
+	}
+
+	var bookmarks *thumbtack.Posts
+	if cmd.Offline {
+		// Answer from the local mirror
+		snapshot, err := mirror.Load(ctx.MirrorDir)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "posts get").
+				Str("app_name", ctx.Appname).
+				Str("mirror_dir", ctx.MirrorDir).
+				Msg("Failed to load local mirror")
+			return err
+		}
+		bookmarks = snapshot.PostsGet(input)
+	} else {
+		// Create thumbtack client
+		client, err := thumbtack.New(
+			thumbtack.WithEndpoint(ctx.Endpoint),
+			thumbtack.WithToken(ctx.Token),
+			thumbtack.WithLogger(ctx.Log),
+			thumbtack.WithUserAgent(ctx.UserAgent),
+		)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "posts get").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to create client")
+			return err
+		}
+
+		// Get bookmarks with params
+		bookmarks, err = client.PostsGet(input)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "posts get").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to get bookmarks")
+			return err
+		}
 	}
 
 	if cmd.Json {
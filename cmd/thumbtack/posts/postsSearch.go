@@ -0,0 +1,96 @@
+package posts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/clictx"
+	"github.com/rmrfslashbin/thumbtack/mirror"
+	"github.com/rmrfslashbin/thumbtack/query"
+)
+
+// PostsSearchCmd is the command to search bookmarks
+type PostsSearchCmd struct {
+	Query   string `arg:"" name:"query" help:"Search query, e.g. 'tag:go -tag:old site:github.com toread:yes after:2023-01-01 \"exact phrase\"'"`
+	Json    bool   `name:"json" help:"Output as JSON" default:"false" type:"bool"`
+	Offline bool   `name:"offline" help:"Answer from the local mirror (see 'thumbtack sync')" default:"false" type:"bool"`
+}
+
+// Run runs the command
+func (cmd *PostsSearchCmd) Run(ctx *clictx.Context) error {
+	// Say hello
+	ctx.Log.Debug().
+		Str("cmd", "posts search").
+		Str("app_name", ctx.Appname).
+		Msg("Running command")
+
+	q, err := query.Parse(cmd.Query)
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "posts search").
+			Str("app_name", ctx.Appname).
+			Str("query", cmd.Query).
+			Msg("Failed to parse query")
+		return err
+	}
+
+	var bookmarks []thumbtack.Bookmark
+	if cmd.Offline {
+		// Answer from the local mirror
+		snapshot, err := mirror.Load(ctx.MirrorDir)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "posts search").
+				Str("app_name", ctx.Appname).
+				Str("mirror_dir", ctx.MirrorDir).
+				Msg("Failed to load local mirror")
+			return err
+		}
+		bookmarks = q.Filter(snapshot.Bookmarks)
+	} else {
+		// Create thumbtack client
+		client, err := thumbtack.New(
+			thumbtack.WithEndpoint(ctx.Endpoint),
+			thumbtack.WithToken(ctx.Token),
+			thumbtack.WithLogger(ctx.Log),
+			thumbtack.WithUserAgent(ctx.UserAgent),
+		)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "posts search").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to create client")
+			return err
+		}
+
+		// Search, pushing what posts/all can filter down to the server
+		bookmarks, err = q.Search(context.Background(), client)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "posts search").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to search bookmarks")
+			return err
+		}
+	}
+
+	if cmd.Json {
+		// Print the result as JSON
+		data, err := json.Marshal(bookmarks)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "posts search").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to marshal bookmarks")
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		// Spew the result
+		spew.Dump(bookmarks)
+	}
+	return nil
+}
@@ -2,17 +2,24 @@ package posts
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/rmrfslashbin/thumbtack"
 	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/clictx"
+	"github.com/rmrfslashbin/thumbtack/mirror"
+	"github.com/rmrfslashbin/thumbtack/suggest"
 )
 
 // PostsSuggestCmd is the command to return one or more posts on a single day matching the arguments
 type PostsSuggestCmd struct {
-	Url  string `name:"url" required:"" help:"URL from which to suggest" type:"string"`
-	Json bool   `name:"json" help:"Output as JSON" default:"false" type:"bool"`
+	Url     string `name:"url" required:"" help:"URL from which to suggest" type:"string"`
+	Title   string `name:"title" help:"Title of the bookmark, used with --local" type:"string"`
+	Local   bool   `name:"local" help:"Rank tags learned from the account's bookmarks, merged with Pinboard's suggestions" default:"false" type:"bool"`
+	Limit   int    `name:"limit" help:"Number of tags to suggest with --local" default:"10" type:"int"`
+	Offline bool   `name:"offline" help:"With --local, learn from the local mirror only and skip Pinboard's suggestions" default:"false" type:"bool"`
+	Json    bool   `name:"json" help:"Output as JSON" default:"false" type:"bool"`
 }
 
 // Run runs the command
@@ -38,19 +45,27 @@ func (cmd *PostsSuggestCmd) Run(ctx *clictx.Context) error {
 		return err
 	}
 
-	// Get suggestions
-	suggests, err := client.PostsSuggest(cmd.Url)
-	if err != nil {
-		ctx.Log.Error().
-			Str("cmd", "posts suggest").
-			Str("app_name", ctx.Appname).
-			Msg("Failed to get suggestions")
-		return err
+	var result interface{}
+	if cmd.Local {
+		result, err = cmd.suggestLocal(ctx, client)
+		if err != nil {
+			return err
+		}
+	} else {
+		// Get suggestions
+		result, err = client.PostsSuggest(cmd.Url)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "posts suggest").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to get suggestions")
+			return err
+		}
 	}
 
 	if cmd.Json {
 		// Print the result as JSON
-		data, err := json.Marshal(suggests)
+		data, err := json.Marshal(result)
 		if err != nil {
 			ctx.Log.Error().
 				Str("cmd", "posts suggest").
@@ -61,7 +76,53 @@ func (cmd *PostsSuggestCmd) Run(ctx *clictx.Context) error {
 		fmt.Println(string(data))
 	} else {
 		// Spew the result
-		spew.Dump(suggests)
+		spew.Dump(result)
 	}
 	return nil
 }
+
+// suggestLocal ranks tags learned from the local mirror, or from all bookmarks if there is no mirror yet,
+// and merges them with Pinboard's suggestions
+func (cmd *PostsSuggestCmd) suggestLocal(ctx *clictx.Context, client *thumbtack.Client) ([]suggest.Suggestion, error) {
+	var bookmarks []thumbtack.Bookmark
+	snapshot, err := mirror.Load(ctx.MirrorDir)
+	switch {
+	case err == nil:
+		bookmarks = snapshot.Bookmarks
+	case errors.Is(err, mirror.ErrNoMirror) && !cmd.Offline:
+		all, err := client.PostsAll(&thumbtack.PostsAllInput{})
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "posts suggest").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to get bookmarks")
+			return nil, err
+		}
+		bookmarks = *all
+	default:
+		ctx.Log.Error().
+			Str("cmd", "posts suggest").
+			Str("app_name", ctx.Appname).
+			Str("mirror_dir", ctx.MirrorDir).
+			Msg("Failed to load local mirror")
+		return nil, err
+	}
+
+	model := suggest.Train(bookmarks)
+	local := model.Suggest(thumbtack.Bookmark{Href: cmd.Url, Description: cmd.Title}, cmd.Limit)
+	if cmd.Offline {
+		return local, nil
+	}
+
+	// Pinboard's suggestions are a bonus; without them the local ones stand on their own
+	remote, err := client.PostsSuggest(cmd.Url)
+	if err != nil {
+		ctx.Log.Warn().
+			Str("cmd", "posts suggest").
+			Str("app_name", ctx.Appname).
+			Err(err).
+			Msg("Failed to get suggestions, using local suggestions only")
+		remote = nil
+	}
+	return suggest.Merge(local, remote, cmd.Limit), nil
+}
@@ -1,8 +1,11 @@
 package root
 
 import (
+	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/backup"
+	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/exchange"
 	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/notes"
 	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/posts"
+	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/sync"
 	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/tags"
 	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/user"
 )
@@ -12,12 +15,19 @@ type CLI struct {
 	// Global flags/args
 	LogLevel  string  `name:"loglevel" env:"LOGLEVEL" default:"info" enum:"panic,fatal,error,warn,info,debug,trace" help:"Set the log level."`
 	Endpoint  *string `name:"endpoint" env:"ENDPOINT" help:"Set the API endpoint."`
+	MirrorDir *string `name:"mirror-dir" env:"MIRROR_DIR" help:"Set the local mirror directory (default: user cache directory)."`
+	Rules     *string `name:"rules" env:"RULES_FILE" help:"Set the auto-tagging rules file (default: rules.yaml, rules.yml or rules.json in the user config directory, if present)."`
 	Token     string  `name:"token" env:"TOKEN" required:"" help:"Set the API token."`
 	UserAgent *string `name:"useragent" env:"USERAGENT" help:"Set the User-Agent header."`
 
 	// Commands
-	Notes notes.NotesCmd `cmd:"" help:"Notes commands."`
-	Posts posts.PostsCmd `cmd:"" help:"Posts commands."`
-	Tags  tags.TagsCmd   `cmd:"" help:"Tags commands."`
-	User  user.UserCmd   `cmd:"" help:"User commands."`
+	Backup  backup.BackupCmd   `cmd:"" help:"Back up the account into an archive file."`
+	Export  exchange.ExportCmd `cmd:"" help:"Export all bookmarks to a file."`
+	Import  exchange.ImportCmd `cmd:"" help:"Import bookmarks from a file."`
+	Notes   notes.NotesCmd     `cmd:"" help:"Notes commands."`
+	Posts   posts.PostsCmd     `cmd:"" help:"Posts commands."`
+	Restore backup.RestoreCmd  `cmd:"" help:"Restore the bookmarks of an archive file into the account."`
+	Sync    sync.SyncCmd       `cmd:"" help:"Sync the account into the local mirror."`
+	Tags    tags.TagsCmd       `cmd:"" help:"Tags commands."`
+	User    user.UserCmd       `cmd:"" help:"User commands."`
 }
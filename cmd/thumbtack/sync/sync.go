@@ -0,0 +1,87 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/clictx"
+	"github.com/rmrfslashbin/thumbtack/mirror"
+)
+
+// SyncCmd is the command to sync the account into the local mirror
+type SyncCmd struct {
+	Verbose bool `name:"verbose" short:"v" help:"List the added, changed and deleted bookmarks" default:"false" type:"bool"`
+	Json    bool `name:"json" help:"Output as JSON" default:"false" type:"bool"`
+}
+
+// Run runs the command
+func (cmd *SyncCmd) Run(ctx *clictx.Context) error {
+	// Say hello
+	ctx.Log.Debug().
+		Str("cmd", "sync").
+		Str("app_name", ctx.Appname).
+		Str("mirror_dir", ctx.MirrorDir).
+		Msg("Running command")
+
+	// Create thumbtack client
+	client, err := thumbtack.New(
+		thumbtack.WithEndpoint(ctx.Endpoint),
+		thumbtack.WithToken(ctx.Token),
+		thumbtack.WithLogger(ctx.Log),
+		thumbtack.WithUserAgent(ctx.UserAgent),
+	)
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "sync").
+			Str("app_name", ctx.Appname).
+			Msg("Failed to create client")
+		return err
+	}
+
+	// Sync the account
+	changes, err := mirror.New(client, ctx.MirrorDir, mirror.WithLogger(ctx.Log)).Sync(context.Background())
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "sync").
+			Str("app_name", ctx.Appname).
+			Msg("Failed to sync")
+		return err
+	}
+
+	if cmd.Json {
+		// Print the result as JSON
+		data, err := json.Marshal(changes)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "sync").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to marshal changes")
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	// Print a summary
+	if !changes.Fetched {
+		fmt.Printf("up to date (last update %s)\n", changes.UpdateTime.Format(time.RFC3339))
+		return nil
+	}
+	fmt.Printf("added: %d, changed: %d, deleted: %d\n", len(changes.Added), len(changes.Changed), len(changes.Deleted))
+	if cmd.Verbose {
+		for _, bookmark := range changes.Added {
+			fmt.Printf("+ %s\n", bookmark.Href)
+		}
+		for _, bookmark := range changes.Changed {
+			fmt.Printf("~ %s\n", bookmark.Href)
+		}
+		for _, bookmark := range changes.Deleted {
+			fmt.Printf("- %s\n", bookmark.Href)
+		}
+	}
+
+	return nil
+}
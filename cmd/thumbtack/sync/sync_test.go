@@ -0,0 +1,7 @@
+package sync
+
+import "testing"
+
+func TestSkip(t *testing.T) {
+	t.Skip("This module provides a reference CLI for the Thumbtack package.")
+}
@@ -3,5 +3,8 @@ package tags
 type TagsCmd struct {
 	All    TagsAllCmd    `cmd:"" help:"Returns all tags."`
 	Delete TagsDeleteCmd `cmd:"" help:"Deletes a tag."`
+	Graph  TagsGraphCmd  `cmd:"" help:"Exports the tag co-occurrence graph or the tag hierarchy."`
+	Lint   TagsLintCmd   `cmd:"" help:"Finds inconsistent tags and proposes renames."`
+	Remap  TagsRemapCmd  `cmd:"" help:"Renames and merges many tags from a mapping file."`
 	Rename TagsRenameCmd `cmd:"" help:"Renames a tag."`
 }
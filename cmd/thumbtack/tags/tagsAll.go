@@ -7,11 +7,13 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/rmrfslashbin/thumbtack"
 	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/clictx"
+	"github.com/rmrfslashbin/thumbtack/mirror"
 )
 
 // TagsAllCmd is the command to get all tags
 type TagsAllCmd struct {
-	Json bool `name:"json" help:"Output as JSON" default:"false" type:"bool"`
+	Json    bool `name:"json" help:"Output as JSON" default:"false" type:"bool"`
+	Offline bool `name:"offline" help:"Answer from the local mirror (see 'thumbtack sync')" default:"false" type:"bool"`
 }
 
 // Run runs the command
@@ -22,29 +24,44 @@ func (cmd *TagsAllCmd) Run(ctx *clictx.Context) error {
 		Str("app_name", ctx.Appname).
 		Msg("Running command")
 
-	// Create thumbtack client
-	client, err := thumbtack.New(
-		thumbtack.WithEndpoint(ctx.Endpoint),
-		thumbtack.WithToken(ctx.Token),
-		thumbtack.WithLogger(ctx.Log),
-		thumbtack.WithUserAgent(ctx.UserAgent),
-	)
-	if err != nil {
-		ctx.Log.Error().
-			Str("cmd", "tags all").
-			Str("app_name", ctx.Appname).
-			Msg("Failed to create client")
-		return err
-	}
+	var tags *thumbtack.Tags
+	if cmd.Offline {
+		// Answer from the local mirror
+		snapshot, err := mirror.Load(ctx.MirrorDir)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "tags all").
+				Str("app_name", ctx.Appname).
+				Str("mirror_dir", ctx.MirrorDir).
+				Msg("Failed to load local mirror")
+			return err
+		}
+		tags = snapshot.Tags()
+	} else {
+		// Create thumbtack client
+		client, err := thumbtack.New(
+			thumbtack.WithEndpoint(ctx.Endpoint),
+			thumbtack.WithToken(ctx.Token),
+			thumbtack.WithLogger(ctx.Log),
+			thumbtack.WithUserAgent(ctx.UserAgent),
+		)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "tags all").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to create client")
+			return err
+		}
 
-	// Get all tags
-	tags, err := client.TagsGet()
-	if err != nil {
-		ctx.Log.Error().
-			Str("cmd", "tags all").
-			Str("app_name", ctx.Appname).
-			Msg("Failed to get tags")
-		return err
+		// Get all tags
+		tags, err = client.TagsGet()
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "tags all").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to get tags")
+			return err
+		}
 	}
 
 	if cmd.Json {
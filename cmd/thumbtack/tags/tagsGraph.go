@@ -0,0 +1,115 @@
+package tags
+
+import (
+	"io"
+	"os"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/analysis"
+	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/clictx"
+	"github.com/rmrfslashbin/thumbtack/mirror"
+)
+
+// TagsGraphCmd is the command to export the tag co-occurrence graph or the tag hierarchy
+type TagsGraphCmd struct {
+	Format     string  `name:"format" help:"Format of the graph (the hierarchy is text unless json)" default:"dot" enum:"dot,json"`
+	Hierarchy  bool    `name:"hierarchy" help:"Write the hierarchy implied by separators in tags (e.g. lang/go) instead of the graph" default:"false" type:"bool"`
+	Separators string  `name:"separators" help:"Separators of hierarchical tags" default:"/:" type:"string"`
+	MinCount   int     `name:"min-count" help:"Leave out tags used by fewer bookmarks" default:"1" type:"int"`
+	MinWeight  int     `name:"min-weight" help:"Leave out pairs of tags sharing fewer bookmarks" default:"1" type:"int"`
+	Output     *string `name:"output" short:"o" help:"Write to this file instead of stdout" type:"string"`
+	Offline    bool    `name:"offline" help:"Answer from the local mirror (see 'thumbtack sync')" default:"false" type:"bool"`
+}
+
+// Run runs the command
+func (cmd *TagsGraphCmd) Run(ctx *clictx.Context) error {
+	// Say hello
+	ctx.Log.Debug().
+		Str("cmd", "tags graph").
+		Str("app_name", ctx.Appname).
+		Str("format", cmd.Format).
+		Msg("Running command")
+
+	var bookmarks []thumbtack.Bookmark
+	if cmd.Offline {
+		// Answer from the local mirror
+		snapshot, err := mirror.Load(ctx.MirrorDir)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "tags graph").
+				Str("app_name", ctx.Appname).
+				Str("mirror_dir", ctx.MirrorDir).
+				Msg("Failed to load local mirror")
+			return err
+		}
+		bookmarks = snapshot.Bookmarks
+	} else {
+		// Create thumbtack client
+		client, err := thumbtack.New(
+			thumbtack.WithEndpoint(ctx.Endpoint),
+			thumbtack.WithToken(ctx.Token),
+			thumbtack.WithLogger(ctx.Log),
+			thumbtack.WithUserAgent(ctx.UserAgent),
+		)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "tags graph").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to create client")
+			return err
+		}
+
+		// Get all bookmarks
+		all, err := client.PostsAll(&thumbtack.PostsAllInput{})
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "tags graph").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to get bookmarks")
+			return err
+		}
+		bookmarks = *all
+	}
+
+	graph := analysis.NewGraph(bookmarks, analysis.WithMinCount(cmd.MinCount), analysis.WithMinWeight(cmd.MinWeight))
+
+	var err error
+	var w io.Writer = os.Stdout
+	var file *os.File
+	if cmd.Output != nil {
+		file, err = os.Create(*cmd.Output)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "tags graph").
+				Str("app_name", ctx.Appname).
+				Str("output", *cmd.Output).
+				Msg("Failed to create output file")
+			return err
+		}
+		w = file
+	}
+
+	switch {
+	case cmd.Hierarchy && cmd.Format == "json":
+		err = analysis.NewHierarchy(graph.Tags(), cmd.Separators).WriteJSON(w)
+	case cmd.Hierarchy:
+		err = analysis.NewHierarchy(graph.Tags(), cmd.Separators).WriteText(w)
+	case cmd.Format == "json":
+		err = graph.WriteJSON(w)
+	default:
+		err = graph.WriteDOT(w)
+	}
+	if file != nil {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "tags graph").
+			Str("app_name", ctx.Appname).
+			Msg("Failed to write graph")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package tags
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/clictx"
+	"github.com/rmrfslashbin/thumbtack/mirror"
+	"github.com/rmrfslashbin/thumbtack/taglint"
+)
+
+// TagsLintCmd is the command to find inconsistent tags
+type TagsLintCmd struct {
+	MaxDistance int    `name:"max-distance" help:"Largest edit distance between near duplicate tags" default:"2" type:"int"`
+	Mapping     string `name:"mapping" help:"Write the proposed renames to this file, for 'tags remap --file'" type:"string"`
+	Json        bool   `name:"json" help:"Output as JSON" default:"false" type:"bool"`
+	Offline     bool   `name:"offline" help:"Answer from the local mirror (see 'thumbtack sync')" default:"false" type:"bool"`
+}
+
+// Run runs the command
+func (cmd *TagsLintCmd) Run(ctx *clictx.Context) error {
+	// Say hello
+	ctx.Log.Debug().
+		Str("cmd", "tags lint").
+		Str("app_name", ctx.Appname).
+		Msg("Running command")
+
+	var tags *thumbtack.Tags
+	if cmd.Offline {
+		// Answer from the local mirror
+		snapshot, err := mirror.Load(ctx.MirrorDir)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "tags lint").
+				Str("app_name", ctx.Appname).
+				Str("mirror_dir", ctx.MirrorDir).
+				Msg("Failed to load local mirror")
+			return err
+		}
+		tags = snapshot.Tags()
+	} else {
+		// Create thumbtack client
+		client, err := thumbtack.New(
+			thumbtack.WithEndpoint(ctx.Endpoint),
+			thumbtack.WithToken(ctx.Token),
+			thumbtack.WithLogger(ctx.Log),
+			thumbtack.WithUserAgent(ctx.UserAgent),
+		)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "tags lint").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to create client")
+			return err
+		}
+
+		// Get all tags
+		tags, err = client.TagsGet()
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "tags lint").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to get tags")
+			return err
+		}
+	}
+
+	report := taglint.Lint(tags, taglint.WithMaxDistance(cmd.MaxDistance))
+
+	if cmd.Mapping != "" {
+		// Write the proposed renames next to the target, then move them into place
+		tmp, err := os.CreateTemp(filepath.Dir(cmd.Mapping), ".thumbtack-mapping-*")
+		if err == nil {
+			err = report.Mapping.Write(tmp)
+			if closeErr := tmp.Close(); err == nil {
+				err = closeErr
+			}
+			if err == nil {
+				err = os.Rename(tmp.Name(), cmd.Mapping)
+			}
+			if err != nil {
+				os.Remove(tmp.Name())
+			}
+		}
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "tags lint").
+				Str("app_name", ctx.Appname).
+				Str("file", cmd.Mapping).
+				Msg("Failed to write mapping")
+			return err
+		}
+	}
+
+	if cmd.Json {
+		// Print the result as JSON
+		data, err := json.Marshal(report)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "tags lint").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to marshal report")
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, issue := range report.Issues {
+		fmt.Printf("%-14s %s: %s\n", issue.Kind, strings.Join(issue.Tags, ", "), issue.Message)
+	}
+	if len(report.Mapping) > 0 {
+		fmt.Println()
+		fmt.Println("proposed renames:")
+		for _, rename := range report.Mapping {
+			fmt.Printf("  %s\n", rename)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package tags
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/cmd/thumbtack/clictx"
+	"github.com/rmrfslashbin/thumbtack/remap"
+)
+
+// TagsRemapCmd is the command to rename and merge many tags from a mapping file
+type TagsRemapCmd struct {
+	File    string  `name:"file" required:"" help:"CSV mapping file of old,new tag pairs, applied in order" type:"existingfile"`
+	Apply   bool    `name:"apply" help:"Apply the plan; without it only the plan is shown" default:"false" type:"bool"`
+	Merge   bool    `name:"merge" help:"Allow renaming onto tags that already exist, merging them" default:"false" type:"bool"`
+	Journal *string `name:"journal" help:"Journal of applied renames, to resume a failed run (default: <file>.journal)" type:"string"`
+	Json    bool    `name:"json" help:"Output as JSON" default:"false" type:"bool"`
+}
+
+// Run runs the command
+func (cmd *TagsRemapCmd) Run(ctx *clictx.Context) error {
+	// Say hello
+	ctx.Log.Debug().
+		Str("cmd", "tags remap").
+		Str("app_name", ctx.Appname).
+		Msg("Running command")
+
+	file, err := os.Open(cmd.File)
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "tags remap").
+			Str("app_name", ctx.Appname).
+			Str("file", cmd.File).
+			Msg("Failed to open mapping")
+		return err
+	}
+	defer file.Close()
+
+	mapping, err := remap.ReadMapping(file)
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "tags remap").
+			Str("app_name", ctx.Appname).
+			Str("file", cmd.File).
+			Msg("Failed to read mapping")
+		return err
+	}
+
+	journal := cmd.File + ".journal"
+	if cmd.Journal != nil {
+		journal = *cmd.Journal
+	}
+	applied, err := remap.ReadJournal(journal)
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "tags remap").
+			Str("app_name", ctx.Appname).
+			Str("journal", journal).
+			Msg("Failed to read journal")
+		return err
+	}
+
+	// Create thumbtack client
+	client, err := thumbtack.New(
+		thumbtack.WithEndpoint(ctx.Endpoint),
+		thumbtack.WithToken(ctx.Token),
+		thumbtack.WithLogger(ctx.Log),
+		thumbtack.WithUserAgent(ctx.UserAgent),
+		thumbtack.WithRateLimiter(thumbtack.NewRateLimiter()),
+	)
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "tags remap").
+			Str("app_name", ctx.Appname).
+			Msg("Failed to create client")
+		return err
+	}
+
+	tags, err := client.TagsGet()
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "tags remap").
+			Str("app_name", ctx.Appname).
+			Msg("Failed to get tags")
+		return err
+	}
+
+	// Check the mapping against the tags of the account
+	plan, err := remap.NewPlan(mapping, tags, remap.WithApplied(applied))
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "tags remap").
+			Str("app_name", ctx.Appname).
+			Str("file", cmd.File).
+			Msg("Failed to plan mapping")
+		return err
+	}
+
+	if !cmd.Apply {
+		// Dry run: show the plan only
+		if cmd.Json {
+			data, err := json.Marshal(plan)
+			if err != nil {
+				ctx.Log.Error().
+					Str("cmd", "tags remap").
+					Str("app_name", ctx.Appname).
+					Msg("Failed to marshal plan")
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+		for _, step := range plan.Steps {
+			line := fmt.Sprintf("%-6s %s (%d bookmarks)", step.Action, step.Rename, step.Count)
+			if step.Conflict {
+				line += ": " + step.New + " already exists"
+			}
+			fmt.Println(line)
+		}
+		if conflicts := plan.Conflicts(); len(conflicts) > 0 && !cmd.Merge {
+			fmt.Printf("%d conflicts; use --merge to merge into existing tags\n", len(conflicts))
+		}
+		fmt.Println("dry run; use --apply to apply the plan")
+		return nil
+	}
+
+	// Apply the plan
+	result, err := remap.Apply(context.Background(), client, plan,
+		remap.WithJournal(journal),
+		remap.WithMerge(cmd.Merge),
+		remap.WithProgress(func(done int, total int) {
+			fmt.Fprintf(os.Stderr, "\rapplied %d/%d", done, total)
+		}),
+	)
+	if result.Renamed+result.Merged+result.Resumed+result.Skipped > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+	if err != nil {
+		ctx.Log.Error().
+			Str("cmd", "tags remap").
+			Str("app_name", ctx.Appname).
+			Str("journal", journal).
+			Int("renamed", result.Renamed).
+			Int("merged", result.Merged).
+			Msg("Failed to apply mapping, run again to resume")
+		return err
+	}
+
+	// The run is complete, so the journal is no longer needed
+	if err := os.Remove(journal); err != nil && !os.IsNotExist(err) {
+		ctx.Log.Warn().
+			Str("cmd", "tags remap").
+			Str("journal", journal).
+			Msg("Failed to remove journal")
+	}
+
+	if cmd.Json {
+		data, err := json.Marshal(result)
+		if err != nil {
+			ctx.Log.Error().
+				Str("cmd", "tags remap").
+				Str("app_name", ctx.Appname).
+				Msg("Failed to marshal result")
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Printf("renamed: %d, merged: %d, resumed: %d, skipped: %d\n", result.Renamed, result.Merged, result.Resumed, result.Skipped)
+	}
+	return nil
+}
@@ -28,13 +28,14 @@ type ErrApiNotSet struct {
 
 // Error returns the error message
 func (e *ErrApiNotSet) Error() string {
-	if e.Msg == "" {
-		e.Msg = "requested api is not set or empty"
+	msg := e.Msg
+	if msg == "" {
+		msg = "requested api is not set or empty"
 	}
 	if e.Api != "" {
-		e.Msg += ": " + e.Api
+		msg += ": " + e.Api
 	}
-	return e.Msg
+	return msg
 }
 
 // ErrUnknownApi is returned when the api is not known
@@ -48,13 +49,14 @@ type ErrUnknownApi struct {
 
 // Error returns the error message
 func (e *ErrUnknownApi) Error() string {
-	if e.Msg == "" {
-		e.Msg = "requested api is not known or defined"
+	msg := e.Msg
+	if msg == "" {
+		msg = "requested api is not known or defined"
 	}
 	if e.Api != "" {
-		e.Msg += ": " + e.Api
+		msg += ": " + e.Api
 	}
-	return e.Msg
+	return msg
 }
 
 // New returns a new Configs struct
@@ -1,6 +1,49 @@
 package thumbtack
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors for the result codes returned by the Pinboard API.
+// Use errors.Is to check for them; the errors returned by the client are ErrUnexpectedResponse.
+var (
+	// ErrItemAlreadyExists is returned by posts/add when the url exists and replace is false
+	ErrItemAlreadyExists = errors.New("item already exists")
+
+	// ErrItemNotFound is returned when the bookmark does not exist
+	ErrItemNotFound = errors.New("item not found")
+
+	// ErrMissingURL is returned when the url is missing
+	ErrMissingURL = errors.New("missing url")
+
+	// ErrMustProvideTitle is returned by posts/add when the title (description) is missing
+	ErrMustProvideTitle = errors.New("must provide title")
+
+	// ErrSomethingWentWrong is the generic failure returned by the Pinboard API
+	ErrSomethingWentWrong = errors.New("something went wrong")
+)
+
+// Sentinel errors for http status codes. Use errors.Is to check for them.
+var (
+	// ErrRateLimited is returned when the server replies 429 Too Many Requests.
+	// A fail-fast RateLimiter refusing a call (ErrRateLimitExceeded) also matches.
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrUnauthorized is returned when the server replies 401 Unauthorized (bad or missing token)
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// resultCodeErrors maps Pinboard result codes to their sentinel errors
+var resultCodeErrors = map[string]error{
+	ErrItemAlreadyExists.Error():  ErrItemAlreadyExists,
+	ErrItemNotFound.Error():       ErrItemNotFound,
+	ErrMissingURL.Error():         ErrMissingURL,
+	ErrMustProvideTitle.Error():   ErrMustProvideTitle,
+	ErrSomethingWentWrong.Error(): ErrSomethingWentWrong,
+}
 
 // ErrBadEndpoint is returned when the endpoint is not valid
 type ErrBadEndpoint struct {
@@ -10,17 +53,24 @@ type ErrBadEndpoint struct {
 
 // Error returns the error message
 func (e *ErrBadEndpoint) Error() string {
-	if e.Msg == "" {
-		e.Msg = "endpoint is not valid"
+	msg := e.Msg
+	if msg == "" {
+		msg = "endpoint is not valid"
 	}
 	if e.Err != nil {
-		e.Msg += ": " + e.Err.Error()
+		msg += ": " + e.Err.Error()
 	}
-	return e.Msg
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *ErrBadEndpoint) Unwrap() error {
+	return e.Err
 }
 
 // ErrBadStatusCode is returned when the status code is not valid
 type ErrBadStatusCode struct {
+	Api        string
 	Err        error
 	Msg        string
 	Status     string
@@ -29,20 +79,37 @@ type ErrBadStatusCode struct {
 
 // Error returns the error message
 func (e *ErrBadStatusCode) Error() string {
-	if e.Msg == "" {
-		e.Msg = "bad status code"
+	msg := e.Msg
+	if msg == "" {
+		msg = "bad status code"
 	}
 	if e.StatusCode != 0 {
-		e.Msg += fmt.Sprintf(": %d", e.StatusCode)
+		msg += fmt.Sprintf(": %d", e.StatusCode)
 	}
 	if e.Status != "" {
-		e.Msg += fmt.Sprintf(" (%s)", e.Status)
+		msg += fmt.Sprintf(" (%s)", e.Status)
 	}
 	if e.Err != nil {
-		e.Msg += ": " + e.Err.Error()
+		msg += ": " + e.Err.Error()
 	}
 
-	return e.Msg
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *ErrBadStatusCode) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the status code matches ErrRateLimited or ErrUnauthorized
+func (e *ErrBadStatusCode) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	}
+	return false
 }
 
 // ErrInvalidInput is returned when the input is not valid
@@ -53,13 +120,19 @@ type ErrInvalidInput struct {
 
 // Error returns the error message
 func (e *ErrInvalidInput) Error() string {
-	if e.Msg == "" {
-		e.Msg = "input is not valid or nil"
+	msg := e.Msg
+	if msg == "" {
+		msg = "input is not valid or nil"
 	}
 	if e.Err != nil {
-		e.Msg += ": " + e.Err.Error()
+		msg += ": " + e.Err.Error()
 	}
-	return e.Msg
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *ErrInvalidInput) Unwrap() error {
+	return e.Err
 }
 
 // ErrMissingInputField is returned when the input is missing
@@ -71,16 +144,22 @@ type ErrMissingInputField struct {
 
 // Error returns the error message
 func (e *ErrMissingInputField) Error() string {
-	if e.Msg == "" {
-		e.Msg = "missing input"
+	msg := e.Msg
+	if msg == "" {
+		msg = "missing input"
 	}
 	if e.Field != "" {
-		e.Msg += ": " + e.Field
+		msg += ": " + e.Field
 	}
 	if e.Err != nil {
-		e.Msg += ": " + e.Err.Error()
+		msg += ": " + e.Err.Error()
 	}
-	return e.Msg
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *ErrMissingInputField) Unwrap() error {
+	return e.Err
 }
 
 // ErrNoToken is returned when no token is provided
@@ -91,17 +170,112 @@ type ErrNoToken struct {
 
 // Error returns the error message
 func (e *ErrNoToken) Error() string {
-	if e.Msg == "" {
-		e.Msg = "no provided. use WithToken()"
+	msg := e.Msg
+	if msg == "" {
+		msg = "no provided. use WithToken()"
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *ErrNoToken) Unwrap() error {
+	return e.Err
+}
+
+// ErrRateLimitExceeded is returned by a fail-fast RateLimiter when a call is not yet allowed
+type ErrRateLimitExceeded struct {
+	Api  string
+	Err  error
+	Msg  string
+	Wait time.Duration
+}
+
+// Error returns the error message
+func (e *ErrRateLimitExceeded) Error() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = "rate limit exceeded"
+	}
+	if e.Api != "" {
+		msg += ": " + e.Api
+	}
+	if e.Wait != 0 {
+		msg += fmt.Sprintf(" (retry in %s)", e.Wait)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *ErrRateLimitExceeded) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrRateLimited
+func (e *ErrRateLimitExceeded) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// ErrRequestCanceled is returned when the request context is canceled or its deadline is exceeded
+type ErrRequestCanceled struct {
+	Api string
+	Err error
+	Msg string
+}
+
+// Error returns the error message
+func (e *ErrRequestCanceled) Error() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = "request canceled"
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying context error
+func (e *ErrRequestCanceled) Unwrap() error {
+	return e.Err
+}
+
+// ErrRetriesExhausted is returned when a call failed on every attempt allowed by the RetryPolicy
+type ErrRetriesExhausted struct {
+	Api      string
+	Attempts int
+	Err      error
+	Msg      string
+}
+
+// Error returns the error message
+func (e *ErrRetriesExhausted) Error() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = "retries exhausted"
+	}
+	if e.Attempts != 0 {
+		msg += fmt.Sprintf(" after %d attempts", e.Attempts)
 	}
 	if e.Err != nil {
-		e.Msg += ": " + e.Err.Error()
+		msg += ": " + e.Err.Error()
 	}
-	return e.Msg
+	return msg
+}
+
+// Unwrap returns the error of the last attempt
+func (e *ErrRetriesExhausted) Unwrap() error {
+	return e.Err
 }
 
 // ErrUnexpectedResponse is returned when the response is not valid
 type ErrUnexpectedResponse struct {
+	Api        string
 	Err        error
 	Msg        string
 	ResultCode string
@@ -109,32 +283,138 @@ type ErrUnexpectedResponse struct {
 
 // Error returns the error message
 func (e *ErrUnexpectedResponse) Error() string {
-	if e.Msg == "" {
-		e.Msg = "unexpected response"
+	msg := e.Msg
+	if msg == "" {
+		msg = "unexpected response"
 	}
 	if e.ResultCode != "" {
-		e.Msg += ": " + e.ResultCode
+		msg += ": " + e.ResultCode
 	}
 	if e.Err != nil {
-		e.Msg += ": " + e.Err.Error()
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *ErrUnexpectedResponse) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the result code matches target, one of the result code sentinel errors
+func (e *ErrUnexpectedResponse) Is(target error) bool {
+	if sentinel, ok := resultCodeErrors[e.ResultCode]; ok {
+		return sentinel == target
 	}
-	return e.Msg
+	return false
 }
 
-// ErrUnmarshalResponse is returned when the response cannot be unmarshalled
+// ErrUnmarshalResponse is returned when the response cannot be unmarshalled.
+// When a single field is at fault, Field names it and Body holds the raw field value.
 type ErrUnmarshalResponse struct {
-	Body []byte
-	Err  error
-	Msg  string
+	Api   string
+	Body  []byte
+	Err   error
+	Field string
+	Msg   string
 }
 
 // Error returns the error message
 func (e *ErrUnmarshalResponse) Error() string {
-	if e.Msg == "" {
-		e.Msg = "failed to unmarshal response"
+	msg := e.Msg
+	if msg == "" {
+		msg = "failed to unmarshal response"
+		if e.Field != "" {
+			msg = fmt.Sprintf("failed to unmarshal field %q", e.Field)
+		}
 	}
 	if e.Err != nil {
-		e.Msg += ": " + e.Err.Error()
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *ErrUnmarshalResponse) Unwrap() error {
+	return e.Err
+}
+
+// ApiOf returns the name of the api (e.g. "PostsAdd") that err was returned for,
+// or "" if err does not carry one.
+func ApiOf(err error) string {
+	for ; err != nil; err = errors.Unwrap(err) {
+		api := ""
+		switch e := err.(type) {
+		case *ErrBadStatusCode:
+			api = e.Api
+		case *ErrRateLimitExceeded:
+			api = e.Api
+		case *ErrRequestCanceled:
+			api = e.Api
+		case *ErrRetriesExhausted:
+			api = e.Api
+		case *ErrUnexpectedResponse:
+			api = e.Api
+		case *ErrUnmarshalResponse:
+			api = e.Api
+		}
+		if api != "" {
+			return api
+		}
+	}
+	return ""
+}
+
+// FieldOf returns the name of the response field that could not be unmarshalled,
+// or "" if err does not carry one.
+func FieldOf(err error) string {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if e, ok := err.(*ErrUnmarshalResponse); ok && e.Field != "" {
+			return e.Field
+		}
+	}
+	return ""
+}
+
+// ResultCodeOf returns the Pinboard result code (e.g. "item already exists") carried by err,
+// or "" if err does not carry one.
+func ResultCodeOf(err error) string {
+	var unexpected *ErrUnexpectedResponse
+	if errors.As(err, &unexpected) {
+		return unexpected.ResultCode
+	}
+	return ""
+}
+
+// StatusCodeOf returns the http status code carried by err, or 0 if err does not carry one.
+func StatusCodeOf(err error) int {
+	var status *ErrBadStatusCode
+	if errors.As(err, &status) {
+		return status.StatusCode
+	}
+	return 0
+}
+
+// setApi records api on err if err is one of the error types carrying an api name and has none yet.
+// It is only used on errors freshly created by the client.
+func setApi(err error, api string) error {
+	switch e := err.(type) {
+	case *ErrBadStatusCode:
+		if e.Api == "" {
+			e.Api = api
+		}
+	case *ErrRateLimitExceeded:
+		if e.Api == "" {
+			e.Api = api
+		}
+	case *ErrRequestCanceled:
+		if e.Api == "" {
+			e.Api = api
+		}
+	case *ErrRetriesExhausted:
+		if e.Api == "" {
+			e.Api = api
+		}
 	}
-	return e.Msg
+	return err
 }
@@ -2,7 +2,10 @@ package thumbtack
 
 import (
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestErrBadEndpoint(t *testing.T) {
@@ -121,6 +124,72 @@ func TestErrNoTokenNoInput(t *testing.T) {
 	}
 }
 
+func TestErrRateLimitExceeded(t *testing.T) {
+	err := ErrRateLimitExceeded{
+		Api:  "PostsAll",
+		Err:  errors.New("Testing subError"),
+		Msg:  "Testing ErrRateLimitExceeded",
+		Wait: 3 * time.Second,
+	}
+	errorOutput := err.Error()
+	expectedOutput := "Testing ErrRateLimitExceeded: PostsAll (retry in 3s): Testing subError"
+	if errorOutput != expectedOutput {
+		t.Errorf("Error() = %v, want %v", errorOutput, expectedOutput)
+	}
+}
+
+func TestErrRateLimitExceededNoInput(t *testing.T) {
+	err := ErrRateLimitExceeded{}
+	errorOutput := err.Error()
+	expectedOutput := "rate limit exceeded"
+	if errorOutput != expectedOutput {
+		t.Errorf("Error() = %v, want %v", errorOutput, expectedOutput)
+	}
+}
+
+func TestErrRequestCanceled(t *testing.T) {
+	err := ErrRequestCanceled{
+		Err: errors.New("Testing subError"),
+		Msg: "Testing ErrRequestCanceled",
+	}
+	errorOutput := err.Error()
+	expectedOutput := "Testing ErrRequestCanceled: Testing subError"
+	if errorOutput != expectedOutput {
+		t.Errorf("Error() = %v, want %v", errorOutput, expectedOutput)
+	}
+}
+
+func TestErrRequestCanceledNoInput(t *testing.T) {
+	err := ErrRequestCanceled{}
+	errorOutput := err.Error()
+	expectedOutput := "request canceled"
+	if errorOutput != expectedOutput {
+		t.Errorf("Error() = %v, want %v", errorOutput, expectedOutput)
+	}
+}
+
+func TestErrRetriesExhausted(t *testing.T) {
+	err := ErrRetriesExhausted{
+		Attempts: 3,
+		Err:      errors.New("Testing subError"),
+		Msg:      "Testing ErrRetriesExhausted",
+	}
+	errorOutput := err.Error()
+	expectedOutput := "Testing ErrRetriesExhausted after 3 attempts: Testing subError"
+	if errorOutput != expectedOutput {
+		t.Errorf("Error() = %v, want %v", errorOutput, expectedOutput)
+	}
+}
+
+func TestErrRetriesExhaustedNoInput(t *testing.T) {
+	err := ErrRetriesExhausted{}
+	errorOutput := err.Error()
+	expectedOutput := "retries exhausted"
+	if errorOutput != expectedOutput {
+		t.Errorf("Error() = %v, want %v", errorOutput, expectedOutput)
+	}
+}
+
 func TestErrUnexpectedResponse(t *testing.T) {
 	err := ErrUnexpectedResponse{
 		Err: errors.New("Testing subError"),
@@ -180,3 +249,152 @@ func TestErrUnmarshalResponseWithMsg(t *testing.T) {
 		t.Errorf("Error() = %v, want %v", errorOutput, expectedOutput)
 	}
 }
+
+func TestErrUnwrap(t *testing.T) {
+	sub := errors.New("Testing subError")
+	errs := []error{
+		&ErrBadEndpoint{Err: sub},
+		&ErrBadStatusCode{Err: sub},
+		&ErrInvalidInput{Err: sub},
+		&ErrMissingInputField{Err: sub},
+		&ErrNoToken{Err: sub},
+		&ErrRateLimitExceeded{Err: sub},
+		&ErrRequestCanceled{Err: sub},
+		&ErrRetriesExhausted{Err: sub},
+		&ErrUnexpectedResponse{Err: sub},
+		&ErrUnmarshalResponse{Err: sub},
+	}
+	for _, err := range errs {
+		if !errors.Is(fmt.Errorf("wrapped: %w", err), sub) {
+			t.Errorf("expected %T to unwrap to the sub error", err)
+		}
+	}
+}
+
+func TestErrUnexpectedResponseIs(t *testing.T) {
+	tests := map[string]error{
+		"item already exists":  ErrItemAlreadyExists,
+		"item not found":       ErrItemNotFound,
+		"missing url":          ErrMissingURL,
+		"must provide title":   ErrMustProvideTitle,
+		"something went wrong": ErrSomethingWentWrong,
+	}
+	for code, sentinel := range tests {
+		var err error = &ErrUnexpectedResponse{ResultCode: code}
+		if !errors.Is(err, sentinel) {
+			t.Errorf("expected result code %q to match %v", code, sentinel)
+		}
+		if errors.Is(err, ErrRateLimited) {
+			t.Errorf("expected result code %q to not match ErrRateLimited", code)
+		}
+		var unexpected *ErrUnexpectedResponse
+		if !errors.As(fmt.Errorf("wrapped: %w", err), &unexpected) || unexpected.ResultCode != code {
+			t.Errorf("expected errors.As to find ErrUnexpectedResponse with result code %q", code)
+		}
+	}
+	if errors.Is(&ErrUnexpectedResponse{ResultCode: "unknown"}, ErrItemNotFound) {
+		t.Errorf("expected unknown result code to not match any sentinel")
+	}
+}
+
+func TestErrBadStatusCodeIs(t *testing.T) {
+	if !errors.Is(&ErrBadStatusCode{StatusCode: 429}, ErrRateLimited) {
+		t.Errorf("expected 429 to match ErrRateLimited")
+	}
+	if !errors.Is(&ErrBadStatusCode{StatusCode: 401}, ErrUnauthorized) {
+		t.Errorf("expected 401 to match ErrUnauthorized")
+	}
+	if errors.Is(&ErrBadStatusCode{StatusCode: 500}, ErrRateLimited) {
+		t.Errorf("expected 500 to not match ErrRateLimited")
+	}
+	if !errors.Is(&ErrRetriesExhausted{Err: &ErrBadStatusCode{StatusCode: 429}}, ErrRateLimited) {
+		t.Errorf("expected exhausted retries of 429 to match ErrRateLimited")
+	}
+	if !errors.Is(&ErrRateLimitExceeded{}, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimitExceeded to match ErrRateLimited")
+	}
+}
+
+func TestErrErrorIsPure(t *testing.T) {
+	errs := []error{
+		&ErrBadEndpoint{Err: errors.New("sub")},
+		&ErrBadStatusCode{StatusCode: 429, Status: "429 Too Many Requests", Err: errors.New("sub")},
+		&ErrInvalidInput{Err: errors.New("sub")},
+		&ErrMissingInputField{Field: "Url", Err: errors.New("sub")},
+		&ErrNoToken{Err: errors.New("sub")},
+		&ErrRateLimitExceeded{Api: "PostsAll", Wait: time.Second},
+		&ErrRequestCanceled{Err: errors.New("sub")},
+		&ErrRetriesExhausted{Attempts: 3, Err: errors.New("sub")},
+		&ErrUnexpectedResponse{ResultCode: "missing url", Err: errors.New("sub")},
+		&ErrUnmarshalResponse{Err: errors.New("sub")},
+		&ErrApiNotSet{Api: "PostsAll"},
+		&ErrUnknownApi{Api: "PostsAll"},
+	}
+	for _, err := range errs {
+		first := err.Error()
+		if second := err.Error(); first != second {
+			t.Errorf("expected %T.Error() to be stable, got %q then %q", err, first, second)
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(err error) {
+				defer wg.Done()
+				if got := err.Error(); got != first {
+					t.Errorf("expected %q, got %q", first, got)
+				}
+			}(err)
+		}
+		wg.Wait()
+	}
+}
+
+func TestErrAccessors(t *testing.T) {
+	var err error = &ErrRetriesExhausted{
+		Api:      "PostsAll",
+		Attempts: 3,
+		Err:      &ErrBadStatusCode{StatusCode: 503},
+	}
+	if got := ApiOf(err); got != "PostsAll" {
+		t.Errorf("ApiOf() = %q, want %q", got, "PostsAll")
+	}
+	if got := StatusCodeOf(err); got != 503 {
+		t.Errorf("StatusCodeOf() = %d, want %d", got, 503)
+	}
+	if got := ResultCodeOf(err); got != "" {
+		t.Errorf("ResultCodeOf() = %q, want empty", got)
+	}
+
+	err = fmt.Errorf("wrapped: %w", &ErrUnexpectedResponse{Api: "PostsAdd", ResultCode: "missing url"})
+	if got := ApiOf(err); got != "PostsAdd" {
+		t.Errorf("ApiOf() = %q, want %q", got, "PostsAdd")
+	}
+	if got := ResultCodeOf(err); got != "missing url" {
+		t.Errorf("ResultCodeOf() = %q, want %q", got, "missing url")
+	}
+	if got := StatusCodeOf(err); got != 0 {
+		t.Errorf("StatusCodeOf() = %d, want 0", got)
+	}
+
+	if ApiOf(nil) != "" || ResultCodeOf(nil) != "" || StatusCodeOf(nil) != 0 {
+		t.Errorf("expected accessors to return zero values for nil")
+	}
+}
+
+func TestErrUnmarshalResponseField(t *testing.T) {
+	err := &ErrUnmarshalResponse{
+		Api: "PostsAll",
+		Err: &ErrUnmarshalResponse{Field: "extended", Err: errors.New("sub")},
+	}
+	expectedOutput := `failed to unmarshal response: failed to unmarshal field "extended": sub`
+	if err.Error() != expectedOutput {
+		t.Errorf("Error() = %v, want %v", err.Error(), expectedOutput)
+	}
+	if FieldOf(err) != "extended" {
+		t.Errorf("FieldOf() = %v, want extended", FieldOf(err))
+	}
+	if FieldOf(errors.New("sub")) != "" {
+		t.Error("expected no field for a plain error")
+	}
+}
@@ -0,0 +1,84 @@
+package mirror
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// ChangeSet is the difference between the local mirror and the account
+type ChangeSet struct {
+	// UpdateTime is the time of the last change to the account, as returned by posts/update
+	UpdateTime time.Time `json:"update_time"`
+
+	// Fetched is false if the update time had not moved and no bookmarks were downloaded
+	Fetched bool `json:"fetched"`
+
+	// Added are the bookmarks that are new in the account
+	Added []thumbtack.Bookmark `json:"added"`
+
+	// Changed are the bookmarks whose meta signature changed, as they are now
+	Changed []thumbtack.Bookmark `json:"changed"`
+
+	// Deleted are the bookmarks that are no longer in the account, as they were
+	Deleted []thumbtack.Bookmark `json:"deleted"`
+}
+
+// Empty reports whether no bookmarks were added, changed or deleted
+func (c *ChangeSet) Empty() bool {
+	return len(c.Added) == 0 && len(c.Changed) == 0 && len(c.Deleted) == 0
+}
+
+// Diff returns the bookmarks added, changed and deleted going from previous to current, each sorted by url.
+// Bookmarks are identified by url and compared by their meta signature,
+// or by their contents if either has no signature.
+func Diff(previous []thumbtack.Bookmark, current []thumbtack.Bookmark) *ChangeSet {
+	changes := &ChangeSet{
+		Added:   []thumbtack.Bookmark{},
+		Changed: []thumbtack.Bookmark{},
+		Deleted: []thumbtack.Bookmark{},
+	}
+
+	before := make(map[string]thumbtack.Bookmark, len(previous))
+	for _, bookmark := range previous {
+		before[bookmark.Href] = bookmark
+	}
+
+	after := make(map[string]bool, len(current))
+	for _, bookmark := range current {
+		after[bookmark.Href] = true
+		prior, ok := before[bookmark.Href]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, bookmark)
+		case changed(prior, bookmark):
+			changes.Changed = append(changes.Changed, bookmark)
+		}
+	}
+
+	for _, bookmark := range previous {
+		if !after[bookmark.Href] {
+			changes.Deleted = append(changes.Deleted, bookmark)
+		}
+	}
+
+	for _, bookmarks := range [][]thumbtack.Bookmark{changes.Added, changes.Changed, changes.Deleted} {
+		sort.Slice(bookmarks, func(i, j int) bool { return bookmarks[i].Href < bookmarks[j].Href })
+	}
+	return changes
+}
+
+// changed reports whether bookmark differs from previous
+func changed(previous thumbtack.Bookmark, bookmark thumbtack.Bookmark) bool {
+	if previous.Meta != "" && bookmark.Meta != "" {
+		return previous.Meta != bookmark.Meta
+	}
+	return previous.Description != bookmark.Description ||
+		previous.Extended != bookmark.Extended ||
+		!previous.Time.Equal(bookmark.Time) ||
+		previous.Shared != bookmark.Shared ||
+		previous.ToRead != bookmark.ToRead ||
+		!reflect.DeepEqual(previous.Tags, bookmark.Tags)
+}
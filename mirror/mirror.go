@@ -0,0 +1,254 @@
+// Package mirror keeps a local on-disk mirror of a Pinboard account.
+//
+// Mirror.Sync calls posts/update first and only downloads the bookmarks (posts/all with meta signatures)
+// when the account's update time has moved since the last sync. The meta signatures are then compared to
+// the local copy to work out which bookmarks were added, changed or deleted:
+//
+//	m := mirror.New(client, "~/.cache/thumbtack")
+//	changes, err := m.Sync(ctx)
+//	fmt.Println(len(changes.Added), len(changes.Changed), len(changes.Deleted))
+//
+// The mirror can be read without a client (or network access) with Load.
+package mirror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rs/zerolog"
+)
+
+// Version is the version of the on-disk mirror format
+const Version = 1
+
+// mirrorFile is the name of the mirror file in the mirror directory
+const mirrorFile = "mirror.json"
+
+// ErrNoMirror is returned by Load when the directory holds no mirror yet
+var ErrNoMirror = errors.New("no local mirror, sync first")
+
+// ErrUnsupportedVersion is returned by Load when the mirror was written in an unknown format version
+type ErrUnsupportedVersion struct {
+	Msg     string
+	Version int
+}
+
+// Error returns the error message
+func (e *ErrUnsupportedVersion) Error() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = "unsupported mirror version"
+	}
+	return fmt.Sprintf("%s: %d", msg, e.Version)
+}
+
+// Snapshot is the local copy of an account
+type Snapshot struct {
+	// Version is the format version, see Version
+	Version int `json:"version"`
+
+	// UpdateTime is the update time of the account when the bookmarks were downloaded
+	UpdateTime time.Time `json:"update_time"`
+
+	// SyncedAt is the time of the last sync, whether or not bookmarks were downloaded
+	SyncedAt time.Time `json:"synced_at"`
+
+	// Bookmarks are all bookmarks of the account, newest first
+	Bookmarks []thumbtack.Bookmark `json:"bookmarks"`
+}
+
+// Tags returns the tags of the mirrored bookmarks and the number of times they are used, like TagsGet
+func (s *Snapshot) Tags() *thumbtack.Tags {
+	tags := &thumbtack.Tags{Tags: map[string]int{}}
+	for _, bookmark := range s.Bookmarks {
+		for _, tag := range bookmark.Tags {
+			tags.Tags[tag]++
+		}
+	}
+	tags.Count = len(tags.Tags)
+	return tags
+}
+
+// Filter returns the mirrored bookmarks having all tags (compared case insensitively) and,
+// if href is not empty, that url
+func (s *Snapshot) Filter(tags []string, href string) []thumbtack.Bookmark {
+	filtered := []thumbtack.Bookmark{}
+	for _, bookmark := range s.Bookmarks {
+		if href != "" && bookmark.Href != href {
+			continue
+		}
+		if bookmark.HasTags(tags...) {
+			filtered = append(filtered, bookmark)
+		}
+	}
+	return filtered
+}
+
+// Option configures a Mirror
+type Option func(m *Mirror)
+
+// Mirror syncs an account into a local directory
+type Mirror struct {
+	// client. the client used to sync
+	client thumbtack.PostsAPI
+
+	// dir. the directory holding the mirror
+	dir string
+
+	// log. if not provided, nothing is logged
+	log *zerolog.Logger
+
+	// now. the clock, for SyncedAt
+	now func() time.Time
+}
+
+// New returns a Mirror syncing the account of client into dir. dir is created on the first sync.
+func New(client thumbtack.PostsAPI, dir string, opts ...Option) *Mirror {
+	m := &Mirror{
+		client: client,
+		dir:    dir,
+		now:    time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if m.log == nil {
+		log := zerolog.Nop()
+		m.log = &log
+	}
+
+	return m
+}
+
+// WithLogger sets the logger for the mirror
+func WithLogger(log *zerolog.Logger) Option {
+	return func(m *Mirror) {
+		m.log = log
+	}
+}
+
+// Load returns the local copy of the account
+func (m *Mirror) Load() (*Snapshot, error) {
+	return Load(m.dir)
+}
+
+// Sync brings the local copy up to date with the account and returns what changed.
+// Bookmarks are only downloaded if posts/update reports a change since the last download;
+// otherwise the returned ChangeSet is empty and not Fetched.
+func (m *Mirror) Sync(ctx context.Context) (*ChangeSet, error) {
+	snapshot, err := Load(m.dir)
+	if errors.Is(err, ErrNoMirror) {
+		snapshot = &Snapshot{Version: Version, Bookmarks: []thumbtack.Bookmark{}}
+	} else if err != nil {
+		return nil, err
+	}
+
+	update, err := m.client.PostsUpdateWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &ChangeSet{
+		UpdateTime: update.UpdateTime,
+		Added:      []thumbtack.Bookmark{},
+		Changed:    []thumbtack.Bookmark{},
+		Deleted:    []thumbtack.Bookmark{},
+	}
+
+	if !snapshot.UpdateTime.IsZero() && !update.UpdateTime.After(snapshot.UpdateTime) {
+		m.log.Debug().
+			Str("function", "mirror::Sync").
+			Time("update_time", update.UpdateTime).
+			Msg("account unchanged since last sync")
+	} else {
+		meta := true
+		bookmarks, err := m.client.PostsAllWithContext(ctx, &thumbtack.PostsAllInput{Meta: &meta})
+		if err != nil {
+			return nil, err
+		}
+
+		changes = Diff(snapshot.Bookmarks, *bookmarks)
+		changes.UpdateTime = update.UpdateTime
+		changes.Fetched = true
+
+		snapshot.Bookmarks = *bookmarks
+		snapshot.UpdateTime = update.UpdateTime
+
+		m.log.Debug().
+			Str("function", "mirror::Sync").
+			Time("update_time", update.UpdateTime).
+			Int("added", len(changes.Added)).
+			Int("changed", len(changes.Changed)).
+			Int("deleted", len(changes.Deleted)).
+			Msg("downloaded bookmarks")
+	}
+
+	snapshot.Version = Version
+	snapshot.SyncedAt = m.now().UTC()
+	if err := save(m.dir, snapshot); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// Load returns the local copy of the account mirrored into dir, or ErrNoMirror
+func Load(dir string) (*Snapshot, error) {
+	data, err := os.ReadFile(filepath.Join(dir, mirrorFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoMirror
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.Version != Version {
+		return nil, &ErrUnsupportedVersion{Version: snapshot.Version}
+	}
+	if snapshot.Bookmarks == nil {
+		snapshot.Bookmarks = []thumbtack.Bookmark{}
+	}
+	sort.SliceStable(snapshot.Bookmarks, func(i, j int) bool {
+		return snapshot.Bookmarks[i].Time.After(snapshot.Bookmarks[j].Time)
+	})
+
+	return snapshot, nil
+}
+
+// save writes snapshot to dir. The file is replaced atomically so an interrupted sync leaves the old copy intact.
+func save(dir string, snapshot *Snapshot) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(dir, mirrorFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	encoder := json.NewEncoder(file)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(snapshot); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), filepath.Join(dir, mirrorFile))
+}
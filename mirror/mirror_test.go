@@ -0,0 +1,127 @@
+package mirror
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/thumbtacktest"
+)
+
+// newTestMirror returns a fake server seeded with two bookmarks and a mirror of it
+func newTestMirror(t *testing.T) (*thumbtacktest.Server, *thumbtack.Client, *Mirror) {
+	srv := thumbtacktest.NewServer("test:abc123")
+	t.Cleanup(srv.Close)
+
+	day := time.Date(2023, 3, 20, 16, 30, 35, 0, time.UTC)
+	srv.SetNow(func() time.Time { return day })
+	srv.AddBookmark(thumbtack.Bookmark{Href: "https://example.com/a", Description: "A", Tags: []string{"go"}, Time: day})
+	srv.AddBookmark(thumbtack.Bookmark{Href: "https://example.com/b", Description: "B", Tags: []string{"go", "test"}, Time: day.Add(time.Hour)})
+
+	client := srv.QuietClient(t)
+
+	return srv, client, New(client, filepath.Join(t.TempDir(), "mirror"))
+}
+
+// TestMirrorSync tests the first sync, an unchanged sync and a sync after changes
+func TestMirrorSync(t *testing.T) {
+	srv, client, mirror := newTestMirror(t)
+	ctx := context.Background()
+
+	if _, err := mirror.Load(); !errors.Is(err, ErrNoMirror) {
+		t.Fatalf("expected ErrNoMirror before the first sync, got %v", err)
+	}
+
+	changes, err := mirror.Sync(ctx)
+	if err != nil {
+		t.Fatalf("failed to sync: %v", err)
+	}
+	if !changes.Fetched || len(changes.Added) != 2 || len(changes.Changed) != 0 || len(changes.Deleted) != 0 {
+		t.Fatalf("unexpected first change set %+v", changes)
+	}
+
+	// nothing changed, so nothing is downloaded
+	requests := srv.Requests()
+	changes, err = mirror.Sync(ctx)
+	if err != nil {
+		t.Fatalf("failed to sync: %v", err)
+	}
+	if changes.Fetched || !changes.Empty() {
+		t.Errorf("expected no download, got %+v", changes)
+	}
+	if srv.Requests()-requests != 1 {
+		t.Errorf("expected only posts/update to be called, got %d requests", srv.Requests()-requests)
+	}
+
+	// add c, change a and delete b
+	href, title := "https://example.com/c", "C"
+	if _, err := client.PostsAdd(&thumbtack.PostsAddInput{Url: &href, Title: &title}); err != nil {
+		t.Fatal(err)
+	}
+	href, title = "https://example.com/a", "A, renamed"
+	if _, err := client.PostsAdd(&thumbtack.PostsAddInput{Url: &href, Title: &title, Tags: []string{"go"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.PostsDelete("https://example.com/b"); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err = mirror.Sync(ctx)
+	if err != nil {
+		t.Fatalf("failed to sync: %v", err)
+	}
+	if !changes.Fetched {
+		t.Fatal("expected bookmarks to be downloaded")
+	}
+	if len(changes.Added) != 1 || changes.Added[0].Href != "https://example.com/c" {
+		t.Errorf("unexpected added %+v", changes.Added)
+	}
+	if len(changes.Changed) != 1 || changes.Changed[0].Description != "A, renamed" {
+		t.Errorf("unexpected changed %+v", changes.Changed)
+	}
+	if len(changes.Deleted) != 1 || changes.Deleted[0].Href != "https://example.com/b" {
+		t.Errorf("unexpected deleted %+v", changes.Deleted)
+	}
+	if !changes.UpdateTime.Equal(srv.UpdateTime()) {
+		t.Errorf("expected update time %v, got %v", srv.UpdateTime(), changes.UpdateTime)
+	}
+
+	snapshot, err := Load(mirror.dir)
+	if err != nil {
+		t.Fatalf("failed to load mirror: %v", err)
+	}
+	if len(snapshot.Bookmarks) != 2 || snapshot.Tags().Tags["go"] != 1 {
+		t.Errorf("unexpected snapshot %+v", snapshot)
+	}
+	if filtered := snapshot.Filter([]string{"GO"}, ""); len(filtered) != 1 || filtered[0].Href != "https://example.com/a" {
+		t.Errorf("unexpected filtered bookmarks %+v", filtered)
+	}
+}
+
+// TestLoadUnsupportedVersion tests that mirrors in an unknown format are rejected
+func TestLoadUnsupportedVersion(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, mirrorFile), []byte(`{"version":99}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	unsupported := &ErrUnsupportedVersion{}
+	if _, err := Load(dir); !errors.As(err, &unsupported) || unsupported.Version != 99 {
+		t.Errorf("expected ErrUnsupportedVersion, got %v", err)
+	}
+}
+
+// TestDiffWithoutMeta tests that bookmarks without meta signatures are compared by content
+func TestDiffWithoutMeta(t *testing.T) {
+	previous := []thumbtack.Bookmark{{Href: "a", Tags: []string{"go"}}, {Href: "b"}}
+	current := []thumbtack.Bookmark{{Href: "a", Tags: []string{"go", "new"}}, {Href: "b"}}
+
+	changes := Diff(previous, current)
+	if len(changes.Changed) != 1 || changes.Changed[0].Href != "a" || len(changes.Added) != 0 || len(changes.Deleted) != 0 {
+		t.Errorf("unexpected change set %+v", changes)
+	}
+}
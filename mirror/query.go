@@ -0,0 +1,86 @@
+package mirror
+
+import (
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// PostsAll answers input from the mirror the way posts/all would. A nil input returns all bookmarks.
+func (s *Snapshot) PostsAll(input *thumbtack.PostsAllInput) []thumbtack.Bookmark {
+	if input == nil {
+		input = &thumbtack.PostsAllInput{}
+	}
+	meta := input.Meta != nil && *input.Meta
+
+	bookmarks := []thumbtack.Bookmark{}
+	for _, bookmark := range s.Filter(input.Tags, "") {
+		if input.FromDT != nil && bookmark.Time.Before(*input.FromDT) {
+			continue
+		}
+		if input.ToDT != nil && bookmark.Time.After(*input.ToDT) {
+			continue
+		}
+		bookmarks = append(bookmarks, withMeta(bookmark, meta))
+	}
+
+	if input.Start != nil && *input.Start > 0 {
+		start := *input.Start
+		if start > len(bookmarks) {
+			start = len(bookmarks)
+		}
+		bookmarks = bookmarks[start:]
+	}
+	if input.Results != nil && *input.Results >= 0 && *input.Results < len(bookmarks) {
+		bookmarks = bookmarks[:*input.Results]
+	}
+
+	return bookmarks
+}
+
+// PostsGet answers input from the mirror the way posts/get would: the bookmarks for a url,
+// or else for a single day, defaulting to the day of the most recent bookmark.
+// User is not known to the mirror and is left empty.
+func (s *Snapshot) PostsGet(input *thumbtack.PostsGetInput) *thumbtack.Posts {
+	if input == nil {
+		input = &thumbtack.PostsGetInput{}
+	}
+	meta := input.Meta == nil || *input.Meta
+
+	posts := &thumbtack.Posts{Posts: []thumbtack.Bookmark{}}
+
+	if input.URL != nil && *input.URL != "" {
+		for _, bookmark := range s.Filter(input.Tags, *input.URL) {
+			posts.Posts = append(posts.Posts, withMeta(bookmark, meta))
+			posts.Date = bookmark.Time
+		}
+		return posts
+	}
+
+	bookmarks := s.Filter(input.Tags, "")
+	var day string
+	switch {
+	case input.Date != nil && !input.Date.IsZero():
+		day = input.Date.Format(time.DateOnly)
+	case len(bookmarks) > 0:
+		day = bookmarks[0].Time.UTC().Format(time.DateOnly)
+	default:
+		return posts
+	}
+	posts.Date, _ = time.Parse(time.DateOnly, day)
+
+	for _, bookmark := range bookmarks {
+		if bookmark.Time.UTC().Format(time.DateOnly) == day {
+			posts.Posts = append(posts.Posts, withMeta(bookmark, meta))
+		}
+	}
+	return posts
+}
+
+// withMeta returns bookmark without its meta signature unless meta is true
+func withMeta(bookmark thumbtack.Bookmark, meta bool) thumbtack.Bookmark {
+	if !meta {
+		bookmark.Meta = ""
+	}
+	return bookmark
+}
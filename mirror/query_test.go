@@ -0,0 +1,65 @@
+package mirror
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// newTestSnapshot returns a snapshot with three bookmarks on two days, newest first
+func newTestSnapshot() *Snapshot {
+	day := time.Date(2023, 3, 20, 16, 30, 35, 0, time.UTC)
+	return &Snapshot{
+		Version: Version,
+		Bookmarks: []thumbtack.Bookmark{
+			{Href: "https://example.com/c", Meta: "c", Tags: []string{"go"}, Time: day.Add(24 * time.Hour)},
+			{Href: "https://example.com/b", Meta: "b", Tags: []string{"go", "test"}, Time: day.Add(time.Hour)},
+			{Href: "https://example.com/a", Meta: "a", Tags: []string{}, Time: day},
+		},
+	}
+}
+
+// TestSnapshotPostsAll tests answering posts/all from a snapshot
+func TestSnapshotPostsAll(t *testing.T) {
+	snapshot := newTestSnapshot()
+
+	if bookmarks := snapshot.PostsAll(nil); len(bookmarks) != 3 || bookmarks[0].Meta != "" {
+		t.Errorf("expected all bookmarks without meta, got %+v", bookmarks)
+	}
+
+	from := time.Date(2023, 3, 20, 17, 0, 0, 0, time.UTC)
+	start, results, meta := 1, 1, true
+	bookmarks := snapshot.PostsAll(&thumbtack.PostsAllInput{Tags: []string{"go"}, FromDT: &from, Meta: &meta})
+	if len(bookmarks) != 2 || bookmarks[0].Meta != "c" {
+		t.Errorf("unexpected bookmarks %+v", bookmarks)
+	}
+
+	bookmarks = snapshot.PostsAll(&thumbtack.PostsAllInput{Start: &start, Results: &results})
+	if len(bookmarks) != 1 || bookmarks[0].Href != "https://example.com/b" {
+		t.Errorf("unexpected page %+v", bookmarks)
+	}
+}
+
+// TestSnapshotPostsGet tests answering posts/get from a snapshot
+func TestSnapshotPostsGet(t *testing.T) {
+	snapshot := newTestSnapshot()
+
+	// defaults to the day of the most recent bookmark
+	posts := snapshot.PostsGet(nil)
+	if len(posts.Posts) != 1 || posts.Posts[0].Href != "https://example.com/c" || posts.Posts[0].Meta != "c" {
+		t.Errorf("unexpected posts %+v", posts)
+	}
+
+	day := time.Date(2023, 3, 20, 0, 0, 0, 0, time.UTC)
+	posts = snapshot.PostsGet(&thumbtack.PostsGetInput{Date: &day})
+	if len(posts.Posts) != 2 || !posts.Date.Equal(day) {
+		t.Errorf("unexpected posts %+v", posts)
+	}
+
+	href := "https://example.com/a"
+	posts = snapshot.PostsGet(&thumbtack.PostsGetInput{URL: &href})
+	if len(posts.Posts) != 1 || !posts.Date.Equal(snapshot.Bookmarks[2].Time) {
+		t.Errorf("unexpected posts %+v", posts)
+	}
+}
@@ -0,0 +1,88 @@
+package netscape
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// ImportOption configures Import
+type ImportOption func(i *importer)
+
+// importer holds the Import options
+type importer struct {
+	// handled. called with each bookmark added or skipped
+	handled func(bookmark thumbtack.Bookmark) error
+
+	// progress. called after each bookmark
+	progress func(done int, total int)
+
+	// replace. whether existing bookmarks are replaced
+	replace bool
+}
+
+// WithReplace sets whether bookmarks already in the account are replaced. Default is false,
+// in which case they are skipped.
+func WithReplace(replace bool) ImportOption {
+	return func(i *importer) {
+		i.replace = replace
+	}
+}
+
+// WithHandled sets a function called with each bookmark once it is added or skipped, e.g. to journal it.
+// An error returned by handled stops the import.
+func WithHandled(handled func(bookmark thumbtack.Bookmark) error) ImportOption {
+	return func(i *importer) {
+		i.handled = handled
+	}
+}
+
+// WithProgress sets a function called after each bookmark with the number of bookmarks done and the total
+func WithProgress(progress func(done int, total int)) ImportOption {
+	return func(i *importer) {
+		i.progress = progress
+	}
+}
+
+// ImportResult counts the bookmarks handled by Import
+type ImportResult struct {
+	// Added is the number of bookmarks added (or replaced)
+	Added int `json:"added"`
+
+	// Skipped is the number of bookmarks already in the account and not replaced
+	Skipped int `json:"skipped"`
+}
+
+// Import adds bookmarks to the account with PostsAdd, keeping their time, shared and toread flags.
+// It stops at the first error, returning the result so far along with it.
+func Import(ctx context.Context, client thumbtack.PostsAPI, bookmarks []thumbtack.Bookmark, opts ...ImportOption) (*ImportResult, error) {
+	i := &importer{}
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	result := &ImportResult{}
+	for n, bookmark := range bookmarks {
+		_, err := client.PostsAddWithContext(ctx, bookmark.PostsAddInput(i.replace))
+		switch {
+		case err == nil:
+			result.Added++
+		case !i.replace && errors.Is(err, thumbtack.ErrItemAlreadyExists):
+			result.Skipped++
+		default:
+			return result, err
+		}
+
+		if i.handled != nil {
+			if err := i.handled(bookmark); err != nil {
+				return result, err
+			}
+		}
+		if i.progress != nil {
+			i.progress(n+1, len(bookmarks))
+		}
+	}
+
+	return result, nil
+}
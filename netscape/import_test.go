@@ -0,0 +1,63 @@
+package netscape
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/thumbtacktest"
+)
+
+// TestImport tests importing with and without replacing existing bookmarks
+func TestImport(t *testing.T) {
+	srv := thumbtacktest.NewServer("test:abc123")
+	defer srv.Close()
+	srv.AddBookmark(thumbtack.Bookmark{Href: "https://example.com", Description: "Existing"})
+
+	client := srv.QuietClient(t)
+
+	day := time.Date(2023, 3, 20, 16, 30, 35, 0, time.UTC)
+	bookmarks := []thumbtack.Bookmark{
+		{Href: "https://example.com", Description: "Imported", Shared: true},
+		{Href: "https://example.org", Extended: "no title", Time: day, ToRead: true, Tags: []string{"go"}},
+	}
+
+	progress := 0
+	result, err := Import(context.Background(), client, bookmarks, WithProgress(func(done int, total int) {
+		progress = done
+		if total != 2 {
+			t.Errorf("expected a total of 2, got %d", total)
+		}
+	}))
+	if err != nil {
+		t.Fatalf("failed to import: %v", err)
+	}
+	if result.Added != 1 || result.Skipped != 1 || progress != 2 {
+		t.Errorf("unexpected result %+v after %d bookmarks", result, progress)
+	}
+
+	imported := srv.Bookmarks()
+	if len(imported) != 2 || imported[0].Description != "Existing" {
+		t.Fatalf("unexpected bookmarks %+v", imported)
+	}
+	org := imported[1]
+	if org.Description != "https://example.org" || !org.Time.Equal(day) || org.Shared || !org.ToRead || org.Tags[0] != "go" {
+		t.Errorf("unexpected imported bookmark %+v", org)
+	}
+
+	handled := []string{}
+	result, err = Import(context.Background(), client, bookmarks[:1], WithReplace(true), WithHandled(func(bookmark thumbtack.Bookmark) error {
+		handled = append(handled, bookmark.Href)
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("failed to import: %v", err)
+	}
+	if result.Added != 1 || srv.Bookmarks()[0].Description != "Imported" {
+		t.Errorf("expected the existing bookmark to be replaced, got %+v", srv.Bookmarks())
+	}
+	if len(handled) != 1 || handled[0] != "https://example.com" {
+		t.Errorf("expected the replaced bookmark to be handled, got %v", handled)
+	}
+}
@@ -0,0 +1,153 @@
+// Package netscape converts between bookmarks and the Netscape bookmark file format
+// (bookmarks.html), which browsers and most bookmark services import and export.
+//
+// Each bookmark is written as
+//
+//	<DT><A HREF="https://example.com" ADD_DATE="1679329835" PRIVATE="0" TOREAD="1" TAGS="go,example">Title</A>
+//	<DD>Description
+//
+// ADD_DATE is the creation time in seconds since the epoch, TAGS is a comma separated list of tags,
+// and PRIVATE and TOREAD are "1" or "0". Folders are ignored on import.
+package netscape
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// header starts every bookmark file
+const header = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+`
+
+// footer ends every bookmark file
+const footer = "</DL><p>\n"
+
+var (
+	// itemPattern matches the start of a list item
+	itemPattern = regexp.MustCompile(`(?i)<DT>`)
+
+	// anchorPattern matches a link and captures its attributes and text
+	anchorPattern = regexp.MustCompile(`(?is)<A\s([^>]*)>(.*?)</A>`)
+
+	// attributePattern matches a single attribute and captures its name and (quoted or unquoted) value
+	attributePattern = regexp.MustCompile(`([A-Za-z_:-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+
+	// descriptionPattern matches a description and captures its text, up to the next list
+	descriptionPattern = regexp.MustCompile(`(?is)<DD>(.*?)(?:</?DL|$)`)
+
+	// tagPattern matches the markup within text
+	tagPattern = regexp.MustCompile(`<[^>]*>`)
+)
+
+// Encode writes bookmarks to w as a Netscape bookmark file
+func Encode(w io.Writer, bookmarks []thumbtack.Bookmark) error {
+	buf := bufio.NewWriter(w)
+	buf.WriteString(header)
+
+	for _, bookmark := range bookmarks {
+		fmt.Fprintf(buf, `<DT><A HREF="%s"`, html.EscapeString(bookmark.Href))
+		if !bookmark.Time.IsZero() {
+			fmt.Fprintf(buf, ` ADD_DATE="%d"`, bookmark.Time.Unix())
+		}
+		fmt.Fprintf(buf, ` PRIVATE="%s" TOREAD="%s"`, flag(!bookmark.Shared), flag(bookmark.ToRead))
+		fmt.Fprintf(buf, ` TAGS="%s">%s</A>`+"\n", html.EscapeString(strings.Join(bookmark.Tags, ",")), html.EscapeString(bookmark.Description))
+		if bookmark.Extended != "" {
+			fmt.Fprintf(buf, "<DD>%s\n", html.EscapeString(bookmark.Extended))
+		}
+	}
+
+	buf.WriteString(footer)
+	return buf.Flush()
+}
+
+// Decode reads the bookmarks of a Netscape bookmark file from r.
+// Whitespace within a tag is replaced by "_", since Pinboard tags cannot contain whitespace.
+func Decode(r io.Reader) ([]thumbtack.Bookmark, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarks := []thumbtack.Bookmark{}
+	items := itemPattern.Split(string(data), -1)
+	for n, item := range items[1:] {
+		anchor := anchorPattern.FindStringSubmatchIndex(item)
+		if anchor == nil {
+			// a folder or separator
+			continue
+		}
+
+		bookmark := thumbtack.Bookmark{
+			Description: text(item[anchor[4]:anchor[5]]),
+			Shared:      true,
+			Tags:        []string{},
+		}
+
+		for _, attribute := range attributePattern.FindAllStringSubmatch(item[anchor[2]:anchor[3]], -1) {
+			value := html.UnescapeString(attribute[2] + attribute[3] + attribute[4])
+			switch strings.ToUpper(attribute[1]) {
+			case "HREF":
+				bookmark.Href = value
+			case "ADD_DATE":
+				seconds, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("bookmark %d: bad ADD_DATE %q: %w", n+1, value, err)
+				}
+				bookmark.Time = time.Unix(seconds, 0).UTC()
+			case "PRIVATE":
+				bookmark.Shared = value != "1"
+			case "TOREAD":
+				bookmark.ToRead = value == "1"
+			case "TAGS":
+				bookmark.Tags = splitTags(value)
+			}
+		}
+
+		if description := descriptionPattern.FindStringSubmatch(item[anchor[1]:]); description != nil {
+			bookmark.Extended = text(description[1])
+		}
+
+		if bookmark.Href == "" {
+			continue
+		}
+		bookmarks = append(bookmarks, bookmark)
+	}
+
+	return bookmarks, nil
+}
+
+// flag returns "1" if value is true, else "0"
+func flag(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+// text returns the unescaped text of an html fragment, without markup and surrounding whitespace
+func text(fragment string) string {
+	return strings.TrimSpace(html.UnescapeString(tagPattern.ReplaceAllString(fragment, "")))
+}
+
+// splitTags splits a comma separated list of tags
+func splitTags(value string) []string {
+	tags := []string{}
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.Join(strings.Fields(tag), "_"); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,101 @@
+package netscape
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// TestRoundTrip tests that encoded bookmarks decode to the same bookmarks
+func TestRoundTrip(t *testing.T) {
+	bookmarks := []thumbtack.Bookmark{
+		{
+			Href:        "https://example.com/?a=1&b=2",
+			Description: `Example <"quoted"> & co`,
+			Extended:    "a longer description",
+			Time:        time.Date(2023, 3, 20, 16, 30, 35, 0, time.UTC),
+			Shared:      false,
+			ToRead:      true,
+			Tags:        []string{"go", ".private", "via:popular"},
+		},
+		{
+			Href:        "https://example.org",
+			Description: "Untagged",
+			Time:        time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+			Shared:      true,
+			Tags:        []string{},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Encode(buf, bookmarks); err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "<!DOCTYPE NETSCAPE-Bookmark-file-1>") {
+		t.Errorf("missing doctype: %s", buf)
+	}
+
+	decoded, err := Decode(buf)
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, bookmarks) {
+		t.Errorf("expected %+v, got %+v", bookmarks, decoded)
+	}
+}
+
+// TestDecodeBrowserExport tests decoding a browser export with folders, lower case markup and no tags
+func TestDecodeBrowserExport(t *testing.T) {
+	data := `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file. -->
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+    <DT><H3 ADD_DATE="1679329835" LAST_MODIFIED="1679329835">Toolbar</H3>
+    <DD>folder description
+    <DL><p>
+        <dt><a href="https://go.dev/" add_date="1679329835" icon="data:image/png;base64,AAAA">The Go
+        Programming Language</a>
+        <dd>Go is an open source
+        programming language.
+        <DT><A HREF='https://example.com' TAGS="two words, go,,">Example &amp; Co</A>
+    </DL><p>
+    <DT><A HREF="">No url</A>
+</DL><p>
+`
+	bookmarks, err := Decode(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if len(bookmarks) != 2 {
+		t.Fatalf("expected 2 bookmarks, got %+v", bookmarks)
+	}
+
+	golang := bookmarks[0]
+	if golang.Href != "https://go.dev/" || golang.Description != "The Go\n        Programming Language" || !golang.Shared || golang.ToRead {
+		t.Errorf("unexpected bookmark %+v", golang)
+	}
+	if !golang.Time.Equal(time.Unix(1679329835, 0)) || len(golang.Tags) != 0 {
+		t.Errorf("unexpected time or tags %+v", golang)
+	}
+	if !strings.HasPrefix(golang.Extended, "Go is an open source") || !strings.HasSuffix(golang.Extended, "programming language.") {
+		t.Errorf("unexpected description %q", golang.Extended)
+	}
+
+	example := bookmarks[1]
+	if example.Description != "Example & Co" || !reflect.DeepEqual(example.Tags, []string{"two_words", "go"}) || example.Extended != "" {
+		t.Errorf("unexpected bookmark %+v", example)
+	}
+}
+
+// TestDecodeBadAddDate tests that a malformed ADD_DATE is an error
+func TestDecodeBadAddDate(t *testing.T) {
+	_, err := Decode(strings.NewReader(`<DL><DT><A HREF="https://example.com" ADD_DATE="yesterday">Example</A></DL>`))
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+}
@@ -1,6 +1,7 @@
 package thumbtack
 
 import (
+	"context"
 	"encoding/json"
 	"net/url"
 )
@@ -8,6 +9,11 @@ import (
 // NoteById returns a single note
 // https://pinboard.in/api/#notes_get
 func (c *Client) NotesById(id string) (*Note, error) {
+	return c.NotesByIdWithContext(context.Background(), id)
+}
+
+// NotesByIdWithContext is the same as NotesById, but uses ctx for cancellation and deadlines.
+func (c *Client) NotesByIdWithContext(ctx context.Context, id string) (*Note, error) {
 	// Set up the query parameters
 	v := url.Values{}
 	v.Set("format", c.format)
@@ -19,13 +25,13 @@ func (c *Client) NotesById(id string) (*Note, error) {
 		return nil, err
 	}
 	path := notesById + "/" + id
-	body, err := c.callEndpoint(path, v.Encode())
+	body, err := c.callEndpoint(ctx, "NotesById", path, v.Encode())
 	if err != nil {
 		c.log.Error().
 			Str("function", "thumbtack::NotesById").
 			Str("endpoint", c.endpoint.String()).
 			Str("path", path).
-			Str("query", v.Encode()).
+			Str("query", c.redact(v.Encode())).
 			Msg("error calling endpoint")
 		return nil, err
 	}
@@ -36,6 +42,7 @@ func (c *Client) NotesById(id string) (*Note, error) {
 	if err != nil {
 		c.log.Error().Msg("error unmarshalling response")
 		return nil, &ErrUnmarshalResponse{
+			Api:  "NotesById",
 			Body: *body,
 			Err:  err,
 		}
@@ -47,6 +54,11 @@ func (c *Client) NotesById(id string) (*Note, error) {
 // NotesList returns a list of the user's notes
 // https://pinboard.in/api/#notes_list
 func (c *Client) NotesList() (*Notes, error) {
+	return c.NotesListWithContext(context.Background())
+}
+
+// NotesListWithContext is the same as NotesList, but uses ctx for cancellation and deadlines.
+func (c *Client) NotesListWithContext(ctx context.Context) (*Notes, error) {
 	// Set up the query parameters
 	v := url.Values{}
 	v.Set("format", c.format)
@@ -57,13 +69,13 @@ func (c *Client) NotesList() (*Notes, error) {
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.callEndpoint(notesList, v.Encode())
+	body, err := c.callEndpoint(ctx, "NotesList", notesList, v.Encode())
 	if err != nil {
 		c.log.Error().
 			Str("function", "thumbtack::NotesList").
 			Str("endpoint", c.endpoint.String()).
 			Str("path", notesList).
-			Str("query", v.Encode()).
+			Str("query", c.redact(v.Encode())).
 			Msg("error calling endpoint")
 		return nil, err
 	}
@@ -74,6 +86,7 @@ func (c *Client) NotesList() (*Notes, error) {
 	if err != nil {
 		c.log.Error().Msg("error unmarshalling response")
 		return nil, &ErrUnmarshalResponse{
+			Api:  "NotesList",
 			Body: *body,
 			Err:  err,
 		}
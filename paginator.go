@@ -0,0 +1,153 @@
+package thumbtack
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultPageSize is the number of bookmarks per page used by PostsAllPaginator
+const DefaultPageSize = 1000
+
+// PaginatorOption configures a PostsAllPaginator
+type PaginatorOption func(p *PostsAllPaginator)
+
+// PostsAllPaginator walks all bookmarks in the user's account page by page,
+// using the start and results parameters of posts/all.
+//
+// The paginator waits between pages to respect the posts/all rate limit. If NextPage fails,
+// the offset is not advanced, so calling NextPage again retries the same page. Offset can be
+// saved as a checkpoint and passed to WithStartOffset to resume in another process.
+//
+// Pinboard returns bookmarks newest first, so bookmarks added while paging shift the pages.
+// Set ToDT on the input to the time paging started to keep the pages stable.
+type PostsAllPaginator struct {
+	// client. the client used to call posts/all
+	client *Client
+
+	// done. true once the last page was returned
+	done bool
+
+	// input. the filters applied to every page; Start and Results are set by the paginator
+	input PostsAllInput
+
+	// interval. the minimum time between two pages
+	interval time.Duration
+
+	// intervalSet. true if the interval was set by an option
+	intervalSet bool
+
+	// lastPage. the time the last page was requested
+	lastPage time.Time
+
+	// offset. the offset of the next page
+	offset int
+
+	// pageSize. the number of bookmarks per page
+	pageSize int
+}
+
+// NewPostsAllPaginator returns a paginator over the bookmarks matching input.
+// The Start and Results fields of input are ignored; use WithStartOffset and WithPageSize instead.
+// Unless WithPageInterval is given, pages are PostsAllRateLimitInterval apart, or as far apart as
+// the client's RateLimiter allows posts/all calls, so a fail-fast limiter never refuses a page.
+func (c *Client) NewPostsAllPaginator(input *PostsAllInput, opts ...PaginatorOption) *PostsAllPaginator {
+	p := &PostsAllPaginator{
+		client:   c,
+		pageSize: DefaultPageSize,
+	}
+	if input != nil {
+		p.input = *input
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if !p.intervalSet {
+		p.interval = PostsAllRateLimitInterval
+		if c.limiter != nil {
+			p.interval = c.limiter.interval("PostsAll")
+		}
+	}
+	if p.pageSize < 1 {
+		p.pageSize = DefaultPageSize
+	}
+	if p.offset < 0 {
+		p.offset = 0
+	}
+
+	return p
+}
+
+// WithPageInterval sets the minimum time between two pages
+func WithPageInterval(interval time.Duration) PaginatorOption {
+	return func(p *PostsAllPaginator) {
+		p.interval = interval
+		p.intervalSet = true
+	}
+}
+
+// WithPageSize sets the number of bookmarks per page
+func WithPageSize(pageSize int) PaginatorOption {
+	return func(p *PostsAllPaginator) {
+		p.pageSize = pageSize
+	}
+}
+
+// WithStartOffset sets the offset of the first page, e.g. a checkpoint saved from Offset
+func WithStartOffset(offset int) PaginatorOption {
+	return func(p *PostsAllPaginator) {
+		p.offset = offset
+	}
+}
+
+// HasMorePages returns false once the last page was returned
+func (p *PostsAllPaginator) HasMorePages() bool {
+	return !p.done
+}
+
+// Offset returns the offset of the next page. Save it to resume later with WithStartOffset.
+func (p *PostsAllPaginator) Offset() int {
+	return p.offset
+}
+
+// NextPage waits for the page interval and returns the next page of bookmarks.
+// A page shorter than the page size is the last one. After the last page, NextPage returns an empty page.
+func (p *PostsAllPaginator) NextPage(ctx context.Context) ([]Bookmark, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if p.done {
+		return []Bookmark{}, nil
+	}
+
+	if !p.lastPage.IsZero() {
+		if err := sleep(ctx, p.interval-time.Since(p.lastPage)); err != nil {
+			return nil, err
+		}
+	}
+
+	input := p.input
+	start := p.offset
+	results := p.pageSize
+	input.Start = &start
+	input.Results = &results
+
+	bookmarks, err := p.client.PostsAllWithContext(ctx, &input)
+	// measured after the call, which is after the rate limiter reserved it
+	p.lastPage = time.Now()
+	if err != nil {
+		p.client.log.Error().
+			Str("function", "thumbtack::PostsAllPaginator").
+			Int("offset", p.offset).
+			Msg("error getting page")
+		return nil, err
+	}
+
+	page := *bookmarks
+	p.offset += len(page)
+	if len(page) < p.pageSize {
+		p.done = true
+	}
+	return page, nil
+}
@@ -0,0 +1,209 @@
+package thumbtack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// newPaginatorTestServer returns a server with count bookmarks that pages with start/results.
+// Requests are recorded in starts; if failAt is a start offset, that page fails once.
+func newPaginatorTestServer(t *testing.T, count int, failAt int, starts *[]int) *httptest.Server {
+	failed := false
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		results, err := strconv.Atoi(r.URL.Query().Get("results"))
+		if err != nil {
+			t.Errorf("expected results to be set, got %q", r.URL.Query().Get("results"))
+		}
+		*starts = append(*starts, start)
+
+		if start == failAt && !failed {
+			failed = true
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+
+		fmt.Fprint(w, "[")
+		for i := start; i < start+results && i < count; i++ {
+			if i > start {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"href":"https:\/\/example.com\/%d","time":"2023-03-20T16:30:35Z","tags":"test"}`, i)
+		}
+		fmt.Fprint(w, "]")
+	}))
+}
+
+// newPaginatorTestClient returns a client for ts
+func newPaginatorTestClient(t *testing.T, ts *httptest.Server, opts ...Option) *Client {
+	token := "test:abc123"
+	useragent := "test/1.0"
+
+	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(zerolog.PanicLevel)
+	url, _ := url.Parse(ts.URL)
+
+	client, err := New(append([]Option{
+		WithEndpoint(url),
+		WithToken(&token),
+		WithLogger(&log),
+		WithUserAgent(&useragent),
+	}, opts...)...)
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+	return client
+}
+
+// TestPostsAllPaginator tests walking all pages
+func TestPostsAllPaginator(t *testing.T) {
+	starts := []int{}
+	ts := newPaginatorTestServer(t, 25, -1, &starts)
+	defer ts.Close()
+	client := newPaginatorTestClient(t, ts)
+
+	paginator := client.NewPostsAllPaginator(nil, WithPageSize(10), WithPageInterval(0))
+	var all []Bookmark
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		all = append(all, page...)
+	}
+
+	if len(all) != 25 {
+		t.Fatalf("expected 25 bookmarks, got %d", len(all))
+	}
+	for i, bookmark := range all {
+		if expected := fmt.Sprintf("https://example.com/%d", i); bookmark.Href != expected {
+			t.Errorf("expected href '%s', got '%s'", expected, bookmark.Href)
+		}
+	}
+	if fmt.Sprint(starts) != "[0 10 20]" {
+		t.Errorf("expected starts [0 10 20], got %v", starts)
+	}
+	if paginator.Offset() != 25 {
+		t.Errorf("expected offset 25, got %d", paginator.Offset())
+	}
+}
+
+// TestPostsAllPaginatorExactPages tests that a full last page is followed by an empty page
+func TestPostsAllPaginatorExactPages(t *testing.T) {
+	starts := []int{}
+	ts := newPaginatorTestServer(t, 20, -1, &starts)
+	defer ts.Close()
+	client := newPaginatorTestClient(t, ts)
+
+	paginator := client.NewPostsAllPaginator(nil, WithPageSize(10), WithPageInterval(0))
+	pages := 0
+	for paginator.HasMorePages() {
+		if _, err := paginator.NextPage(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pages++
+	}
+	if pages != 3 {
+		t.Errorf("expected 3 pages, got %d", pages)
+	}
+
+	page, err := paginator.NextPage(context.Background())
+	if err != nil || len(page) != 0 {
+		t.Errorf("expected an empty page after the last one, got %d bookmarks and %v", len(page), err)
+	}
+}
+
+// TestPostsAllPaginatorResume tests retrying a failed page and resuming from a checkpoint
+func TestPostsAllPaginatorResume(t *testing.T) {
+	starts := []int{}
+	ts := newPaginatorTestServer(t, 25, 10, &starts)
+	defer ts.Close()
+	client := newPaginatorTestClient(t, ts)
+
+	paginator := client.NewPostsAllPaginator(nil, WithPageSize(10), WithPageInterval(0))
+	if _, err := paginator.NextPage(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := paginator.NextPage(context.Background()); err == nil {
+		t.Fatalf("expected the second page to fail")
+	}
+	checkpoint := paginator.Offset()
+	if checkpoint != 10 {
+		t.Fatalf("expected offset to stay at 10 after a failure, got %d", checkpoint)
+	}
+
+	resumed := client.NewPostsAllPaginator(nil, WithPageSize(10), WithPageInterval(0), WithStartOffset(checkpoint))
+	var rest []Bookmark
+	for resumed.HasMorePages() {
+		page, err := resumed.NextPage(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rest = append(rest, page...)
+	}
+	if len(rest) != 15 || rest[0].Href != "https://example.com/10" {
+		t.Errorf("expected 15 bookmarks starting at 10, got %d", len(rest))
+	}
+}
+
+// TestPostsAllPaginatorInterval tests that pages are spaced by the page interval
+func TestPostsAllPaginatorInterval(t *testing.T) {
+	starts := []int{}
+	ts := newPaginatorTestServer(t, 15, -1, &starts)
+	defer ts.Close()
+	client := newPaginatorTestClient(t, ts)
+
+	paginator := client.NewPostsAllPaginator(nil, WithPageSize(5), WithPageInterval(30*time.Millisecond))
+	start := time.Now()
+	for paginator.HasMorePages() {
+		if _, err := paginator.NextPage(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected 4 pages to take at least 90ms, took %s", elapsed)
+	}
+
+	// the default interval is the posts/all rate limit, so a canceled context stops the wait
+	paginator = client.NewPostsAllPaginator(nil, WithPageSize(5))
+	if _, err := paginator.NextPage(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := paginator.NextPage(ctx); err == nil {
+		t.Fatalf("expected the wait for the rate limit to be canceled")
+	}
+}
+
+// TestPostsAllPaginatorFailFastLimiter tests that pages are spaced by the interval of a fail-fast limiter
+func TestPostsAllPaginatorFailFastLimiter(t *testing.T) {
+	starts := []int{}
+	ts := newPaginatorTestServer(t, 15, -1, &starts)
+	defer ts.Close()
+	limiter := NewRateLimiter(
+		WithRateLimitDefault(0),
+		WithRateLimitInterval("PostsAll", 30*time.Millisecond),
+		WithRateLimitFailFast(true),
+	)
+	client := newPaginatorTestClient(t, ts, WithRateLimiter(limiter))
+
+	paginator := client.NewPostsAllPaginator(nil, WithPageSize(5))
+	for paginator.HasMorePages() {
+		if _, err := paginator.NextPage(context.Background()); err != nil {
+			t.Fatalf("unexpected error at offset %d: %v", paginator.Offset(), err)
+		}
+	}
+	if len(starts) != 4 {
+		t.Errorf("expected 4 pages, got %d", len(starts))
+	}
+}
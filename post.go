@@ -1,11 +1,11 @@
 package thumbtack
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"strconv"
-	"strings"
 	"time"
 )
 
@@ -48,9 +48,39 @@ type PostsAddInput struct {
 	ToRead *bool
 }
 
+// PostsAddInput returns the input for PostsAdd recreating the bookmark with its time, shared and toread flags.
+// A bookmark without a title is titled with its url, since Pinboard requires one.
+func (bookmark Bookmark) PostsAddInput(replace bool) *PostsAddInput {
+	title := bookmark.Description
+	if title == "" {
+		title = bookmark.Href
+	}
+
+	input := &PostsAddInput{
+		Url:     &bookmark.Href,
+		Title:   &title,
+		Replace: &replace,
+		Shared:  &bookmark.Shared,
+		Tags:    bookmark.Tags,
+		ToRead:  &bookmark.ToRead,
+	}
+	if bookmark.Extended != "" {
+		input.Description = &bookmark.Extended
+	}
+	if !bookmark.Time.IsZero() {
+		input.Timestamp = &bookmark.Time
+	}
+	return input
+}
+
 // PostsAdd Add a bookmark
 // https://pinboard.in/api/#posts_add
 func (c *Client) PostsAdd(input *PostsAddInput) (*Result, error) {
+	return c.PostsAddWithContext(context.Background(), input)
+}
+
+// PostsAddWithContext is the same as PostsAdd, but uses ctx for cancellation and deadlines.
+func (c *Client) PostsAddWithContext(ctx context.Context, input *PostsAddInput) (*Result, error) {
 	// Input validation
 	if input == nil {
 		return nil, &ErrInvalidInput{}
@@ -64,8 +94,9 @@ func (c *Client) PostsAdd(input *PostsAddInput) (*Result, error) {
 		return nil, &ErrMissingInputField{Field: "Title"}
 	}
 
-	if len(input.Tags) > 100 {
-		return nil, &ErrInvalidInput{Msg: "tags must be less than or equal to 100"}
+	tags, err := joinTags(input.Tags, 100)
+	if err != nil {
+		return nil, err
 	}
 
 	// Set up the query parameters
@@ -95,8 +126,8 @@ func (c *Client) PostsAdd(input *PostsAddInput) (*Result, error) {
 		v.Set("shared", shared)
 	}
 
-	if len(input.Tags) > 0 {
-		v.Set("tags", strings.Join(input.Tags, " "))
+	if tags != "" {
+		v.Set("tags", tags)
 	}
 
 	if input.Timestamp != nil {
@@ -120,13 +151,13 @@ func (c *Client) PostsAdd(input *PostsAddInput) (*Result, error) {
 		return nil, err
 	}
 
-	body, err := c.callEndpoint(postsAdd, v.Encode())
+	body, err := c.callEndpoint(ctx, "PostsAdd", postsAdd, v.Encode())
 	if err != nil {
 		c.log.Error().
 			Str("function", "thumbtack::PostsAdd").
 			Str("endpoint", c.endpoint.String()).
 			Str("path", postsAdd).
-			Str("query", v.Encode()).
+			Str("query", c.redact(v.Encode())).
 			Msg("error calling endpoint")
 		return nil, err
 	}
@@ -137,13 +168,14 @@ func (c *Client) PostsAdd(input *PostsAddInput) (*Result, error) {
 	if err != nil {
 		c.log.Error().Msg("error unmarshalling response")
 		return nil, &ErrUnmarshalResponse{
+			Api:  "PostsAdd",
 			Body: *body,
 			Err:  err,
 		}
 	}
 
 	if result.ResultCode != "done" {
-		return nil, &ErrUnexpectedResponse{ResultCode: result.ResultCode}
+		return nil, &ErrUnexpectedResponse{Api: "PostsAdd", ResultCode: result.ResultCode}
 	}
 
 	return result, nil
@@ -173,6 +205,49 @@ type PostsAllInput struct {
 // PostsAll Returns all bookmarks in the user's account.
 // https://pinboard.in/api/#posts_all
 func (c *Client) PostsAll(input *PostsAllInput) (*[]Bookmark, error) {
+	return c.PostsAllWithContext(context.Background(), input)
+}
+
+// PostsAllWithContext is the same as PostsAll, but uses ctx for cancellation and deadlines.
+func (c *Client) PostsAllWithContext(ctx context.Context, input *PostsAllInput) (*[]Bookmark, error) {
+	v, err := c.postsAllQuery(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call the endpoint
+	postsAll, err := c.configs.GetAPI("PostsAll")
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.callEndpoint(ctx, "PostsAll", postsAll, v.Encode())
+	if err != nil {
+		c.log.Error().
+			Str("function", "thumbtack::PostsAll").
+			Str("endpoint", c.endpoint.String()).
+			Str("path", postsAll).
+			Str("query", c.redact(v.Encode())).
+			Msg("error calling endpoint")
+		return nil, err
+	}
+
+	// Unmarshal the response
+	bookmarks := &[]Bookmark{}
+	err = json.Unmarshal(*body, bookmarks)
+	if err != nil {
+		c.log.Error().Msg("error unmarshalling response")
+		return nil, &ErrUnmarshalResponse{
+			Api:  "PostsAll",
+			Body: *body,
+			Err:  err,
+		}
+	}
+
+	return bookmarks, nil
+}
+
+// postsAllQuery returns the query parameters for posts/all
+func (c *Client) postsAllQuery(input *PostsAllInput) (url.Values, error) {
 	if input == nil {
 		input = &PostsAllInput{}
 	}
@@ -203,61 +278,41 @@ func (c *Client) PostsAll(input *PostsAllInput) (*[]Bookmark, error) {
 
 	// Convert string slice to a string; check for max length
 
-	if len(input.Tags) > 3 {
-		return nil, &ErrInvalidInput{Msg: "tags must be less than or equal to 3"}
-	}
-	if len(input.Tags) > 0 {
-		v.Set("tag", strings.Join(input.Tags, " "))
-	}
-
-	if input.ToDT != nil {
-		v.Set("todt", input.ToDT.Format(c.dateTimeFormat))
-	}
-
-	// Call the endpoint
-	postsAll, err := c.configs.GetAPI("PostsAll")
+	tag, err := joinTags(input.Tags, 3)
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.callEndpoint(postsAll, v.Encode())
-	if err != nil {
-		c.log.Error().
-			Str("function", "thumbtack::PostsAll").
-			Str("endpoint", c.endpoint.String()).
-			Str("path", postsAll).
-			Str("query", v.Encode()).
-			Msg("error calling endpoint")
-		return nil, err
+	if tag != "" {
+		v.Set("tag", tag)
 	}
 
-	// Unmarshal the response
-	bookmarks := &[]Bookmark{}
-	err = json.Unmarshal(*body, bookmarks)
-	if err != nil {
-		c.log.Error().Msg("error unmarshalling response")
-		return nil, &ErrUnmarshalResponse{
-			Body: *body,
-			Err:  err,
-		}
+	if input.ToDT != nil {
+		v.Set("todt", input.ToDT.Format(c.dateTimeFormat))
 	}
 
-	return bookmarks, nil
+	return v, nil
 }
 
 // PostsDates returns a list of dates with the number of posts at each date.
 // https://pinboard.in/api/#posts_dates
 func (c *Client) PostsDates(tags []string) (*Dates, error) {
+	return c.PostsDatesWithContext(context.Background(), tags)
+}
+
+// PostsDatesWithContext is the same as PostsDates, but uses ctx for cancellation and deadlines.
+func (c *Client) PostsDatesWithContext(ctx context.Context, tags []string) (*Dates, error) {
 	// Set up the query parameters
 	v := url.Values{}
 	v.Set("format", c.format)
 	v.Set("auth_token", *c.token)
 
 	// Convert string slice to a string; check for max length
-	if len(tags) > 3 {
-		return nil, &ErrInvalidInput{Msg: "tags must be less than or equal to 3"}
+	tag, err := joinTags(tags, 3)
+	if err != nil {
+		return nil, err
 	}
-	if len(tags) > 0 {
-		v.Set("tag", strings.Join(tags, " "))
+	if tag != "" {
+		v.Set("tag", tag)
 	}
 
 	// Call the endpoint
@@ -265,13 +320,13 @@ func (c *Client) PostsDates(tags []string) (*Dates, error) {
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.callEndpoint(postsDates, v.Encode())
+	body, err := c.callEndpoint(ctx, "PostsDates", postsDates, v.Encode())
 	if err != nil {
 		c.log.Error().
 			Str("function", "thumbtack::PostsDates").
 			Str("endpoint", c.endpoint.String()).
 			Str("path", postsDates).
-			Str("query", v.Encode()).
+			Str("query", c.redact(v.Encode())).
 			Msg("error calling endpoint")
 		return nil, err
 	}
@@ -282,6 +337,7 @@ func (c *Client) PostsDates(tags []string) (*Dates, error) {
 	if err != nil {
 		c.log.Error().Msg("error unmarshalling response")
 		return nil, &ErrUnmarshalResponse{
+			Api:  "PostsDates",
 			Body: *body,
 			Err:  err,
 		}
@@ -293,6 +349,11 @@ func (c *Client) PostsDates(tags []string) (*Dates, error) {
 // PostsDelete deletes a bookmark
 // https://pinboard.in/api/#posts_delete
 func (c *Client) PostsDelete(urlToDelete string) (*Result, error) {
+	return c.PostsDeleteWithContext(context.Background(), urlToDelete)
+}
+
+// PostsDeleteWithContext is the same as PostsDelete, but uses ctx for cancellation and deadlines.
+func (c *Client) PostsDeleteWithContext(ctx context.Context, urlToDelete string) (*Result, error) {
 	// Set up the query parameters
 	v := url.Values{}
 	v.Set("format", c.format)
@@ -304,13 +365,13 @@ func (c *Client) PostsDelete(urlToDelete string) (*Result, error) {
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.callEndpoint(postsDelete, v.Encode())
+	body, err := c.callEndpoint(ctx, "PostsDelete", postsDelete, v.Encode())
 	if err != nil {
 		c.log.Error().
 			Str("function", "thumbtack::PostsDelete").
 			Str("endpoint", c.endpoint.String()).
 			Str("path", postsDelete).
-			Str("query", v.Encode()).
+			Str("query", c.redact(v.Encode())).
 			Msg("error calling endpoint")
 		return nil, err
 	}
@@ -321,13 +382,14 @@ func (c *Client) PostsDelete(urlToDelete string) (*Result, error) {
 	if err != nil {
 		c.log.Error().Msg("error unmarshalling response")
 		return nil, &ErrUnmarshalResponse{
+			Api:  "PostsDelete",
 			Body: *body,
 			Err:  err,
 		}
 	}
 
 	if result.ResultCode != "done" {
-		return nil, &ErrUnexpectedResponse{ResultCode: result.ResultCode}
+		return nil, &ErrUnexpectedResponse{Api: "PostsDelete", ResultCode: result.ResultCode}
 	}
 
 	return result, nil
@@ -353,6 +415,11 @@ type PostsGetInput struct {
 // If no date or url is given, date of most recent bookmark will be used.
 // https://pinboard.in/api/#posts_get
 func (c *Client) PostsGet(input *PostsGetInput) (*Posts, error) {
+	return c.PostsGetWithContext(context.Background(), input)
+}
+
+// PostsGetWithContext is the same as PostsGet, but uses ctx for cancellation and deadlines.
+func (c *Client) PostsGetWithContext(ctx context.Context, input *PostsGetInput) (*Posts, error) {
 	if input == nil {
 		input = &PostsGetInput{}
 	}
@@ -370,11 +437,12 @@ func (c *Client) PostsGet(input *PostsGetInput) (*Posts, error) {
 	v.Set("meta", meta)
 
 	// Convert string slice to a string; check for max length
-	if len(input.Tags) > 3 {
-		return nil, &ErrInvalidInput{Msg: "tags must be less than or equal to 3"}
+	tag, err := joinTags(input.Tags, 3)
+	if err != nil {
+		return nil, err
 	}
-	if len(input.Tags) > 0 {
-		v.Set("tag", strings.Join(input.Tags, " "))
+	if tag != "" {
+		v.Set("tag", tag)
 	}
 
 	if input.Date != nil {
@@ -390,13 +458,13 @@ func (c *Client) PostsGet(input *PostsGetInput) (*Posts, error) {
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.callEndpoint(postsGet, v.Encode())
+	body, err := c.callEndpoint(ctx, "PostsGet", postsGet, v.Encode())
 	if err != nil {
 		c.log.Error().
 			Str("function", "thumbtack::PostsGet").
 			Str("endpoint", c.endpoint.String()).
 			Str("path", postsGet).
-			Str("query", v.Encode()).
+			Str("query", c.redact(v.Encode())).
 			Msg("error calling endpoint")
 		return nil, err
 	}
@@ -407,6 +475,7 @@ func (c *Client) PostsGet(input *PostsGetInput) (*Posts, error) {
 	if err != nil {
 		c.log.Error().Msg("error unmarshalling response")
 		return nil, &ErrUnmarshalResponse{
+			Api:  "PostsGet",
 			Body: *body,
 			Err:  err,
 		}
@@ -427,6 +496,11 @@ type PostsRecentInput struct {
 // PostsRecent returns recent posts, filtered by tag.
 // https://pinboard.in/api/#posts_recent
 func (c *Client) PostsRecent(input *PostsRecentInput) (*Posts, error) {
+	return c.PostsRecentWithContext(context.Background(), input)
+}
+
+// PostsRecentWithContext is the same as PostsRecent, but uses ctx for cancellation and deadlines.
+func (c *Client) PostsRecentWithContext(ctx context.Context, input *PostsRecentInput) (*Posts, error) {
 	if input == nil {
 		input = &PostsRecentInput{}
 	}
@@ -446,11 +520,12 @@ func (c *Client) PostsRecent(input *PostsRecentInput) (*Posts, error) {
 	v.Set("count", strconv.Itoa(count))
 
 	// Convert string slice to a string; check for max length
-	if len(input.Tags) > 3 {
-		return nil, &ErrInvalidInput{Msg: "tags must be less than or equal to 3"}
+	tag, err := joinTags(input.Tags, 3)
+	if err != nil {
+		return nil, err
 	}
-	if len(input.Tags) > 0 {
-		v.Set("tag", strings.Join(input.Tags, " "))
+	if tag != "" {
+		v.Set("tag", tag)
 	}
 
 	// Call the endpoint
@@ -458,13 +533,13 @@ func (c *Client) PostsRecent(input *PostsRecentInput) (*Posts, error) {
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.callEndpoint(postsRecent, v.Encode())
+	body, err := c.callEndpoint(ctx, "PostsRecent", postsRecent, v.Encode())
 	if err != nil {
 		c.log.Error().
 			Str("function", "thumbtack::PostsRecent").
 			Str("endpoint", c.endpoint.String()).
 			Str("path", postsRecent).
-			Str("query", v.Encode()).
+			Str("query", c.redact(v.Encode())).
 			Msg("error calling endpoint")
 		return nil, err
 	}
@@ -475,6 +550,7 @@ func (c *Client) PostsRecent(input *PostsRecentInput) (*Posts, error) {
 	if err != nil {
 		c.log.Error().Msg("error unmarshalling response")
 		return nil, &ErrUnmarshalResponse{
+			Api:  "PostsRecent",
 			Body: *body,
 			Err:  err,
 		}
@@ -486,6 +562,11 @@ func (c *Client) PostsRecent(input *PostsRecentInput) (*Posts, error) {
 // PostsSuggest returns a list of popular tags and recommended tags for a given URL.
 // https://pinboard.in/api/#posts_suggest
 func (c *Client) PostsSuggest(urlToSuggest string) (*Suggestions, error) {
+	return c.PostsSuggestWithContext(context.Background(), urlToSuggest)
+}
+
+// PostsSuggestWithContext is the same as PostsSuggest, but uses ctx for cancellation and deadlines.
+func (c *Client) PostsSuggestWithContext(ctx context.Context, urlToSuggest string) (*Suggestions, error) {
 	// Set up the query parameters
 	v := url.Values{}
 	v.Set("format", c.format)
@@ -497,13 +578,13 @@ func (c *Client) PostsSuggest(urlToSuggest string) (*Suggestions, error) {
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.callEndpoint(postsSuggest, v.Encode())
+	body, err := c.callEndpoint(ctx, "PostsSuggest", postsSuggest, v.Encode())
 	if err != nil {
 		c.log.Error().
 			Str("function", "thumbtack::PostsSuggest").
 			Str("endpoint", c.endpoint.String()).
 			Str("path", postsSuggest).
-			Str("query", v.Encode()).
+			Str("query", c.redact(v.Encode())).
 			Msg("error calling endpoint")
 		return nil, err
 	}
@@ -514,6 +595,7 @@ func (c *Client) PostsSuggest(urlToSuggest string) (*Suggestions, error) {
 	if err != nil {
 		c.log.Error().Msg("error unmarshalling response")
 		return nil, &ErrUnmarshalResponse{
+			Api:  "PostsSuggest",
 			Body: *body,
 			Err:  err,
 		}
@@ -526,6 +608,11 @@ func (c *Client) PostsSuggest(urlToSuggest string) (*Suggestions, error) {
 // Use this before calling posts/all to see if the data has changed since the last fetch.
 // https://pinboard.in/api/#posts_update
 func (c *Client) PostsUpdate() (*UpdateTime, error) {
+	return c.PostsUpdateWithContext(context.Background())
+}
+
+// PostsUpdateWithContext is the same as PostsUpdate, but uses ctx for cancellation and deadlines.
+func (c *Client) PostsUpdateWithContext(ctx context.Context) (*UpdateTime, error) {
 	// Set up the query parameters
 	v := url.Values{}
 	v.Set("format", c.format)
@@ -536,13 +623,13 @@ func (c *Client) PostsUpdate() (*UpdateTime, error) {
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.callEndpoint(postsUpdate, v.Encode())
+	body, err := c.callEndpoint(ctx, "PostsUpdate", postsUpdate, v.Encode())
 	if err != nil {
 		c.log.Error().
 			Str("function", "thumbtack::PostsUpdate").
 			Str("endpoint", c.endpoint.String()).
 			Str("path", postsUpdate).
-			Str("query", v.Encode()).
+			Str("query", c.redact(v.Encode())).
 			Msg("error calling endpoint")
 		return nil, err
 	}
@@ -553,6 +640,7 @@ func (c *Client) PostsUpdate() (*UpdateTime, error) {
 	if err != nil {
 		c.log.Error().Msg("error unmarshalling response")
 		return nil, &ErrUnmarshalResponse{
+			Api:  "PostsUpdate",
 			Body: *body,
 			Err:  err,
 		}
@@ -1,6 +1,7 @@
 package thumbtack
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -263,6 +264,42 @@ func TestPostsAddErrorResult(t *testing.T) {
 	}
 }
 
+// TestPostsAddItemAlreadyExists tests that the result code can be matched with errors.Is
+func TestPostsAddItemAlreadyExists(t *testing.T) {
+	token := "test:abc123"
+	useragent := "test/1.0"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result_code":"item already exists"}`)
+	}))
+	defer ts.Close()
+
+	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(zerolog.PanicLevel)
+	url, _ := url.Parse(ts.URL)
+
+	client, err := New(
+		WithEndpoint(url),
+		WithToken(&token),
+		WithLogger(&log),
+		WithUserAgent(&useragent),
+	)
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+
+	addUrl := "https://example.com"
+	addTitle := "Example Title"
+	addFalse := false
+	_, err = client.PostsAdd(&PostsAddInput{
+		Url:     &addUrl,
+		Title:   &addTitle,
+		Replace: &addFalse,
+	})
+	if !errors.Is(err, ErrItemAlreadyExists) {
+		t.Fatalf("expected error to match ErrItemAlreadyExists, got %v", err)
+	}
+}
+
 // TestPostsAddInputFieldsTrue tests the PostsAdd method with input fields set to true
 func TestPostsAddInputFieldsTrue(t *testing.T) {
 
@@ -1889,3 +1926,24 @@ func TestPostsUpdateResponseData(t *testing.T) {
 	}
 
 }
+
+// TestBookmarkPostsAddInput tests that a bookmark converts to the input recreating it
+func TestBookmarkPostsAddInput(t *testing.T) {
+	day := time.Date(2023, 3, 20, 16, 30, 35, 0, time.UTC)
+	bookmark := Bookmark{Href: "https://example.com", Time: day, ToRead: true, Tags: []string{"go"}}
+
+	input := bookmark.PostsAddInput(false)
+	if *input.Url != bookmark.Href || *input.Title != bookmark.Href || input.Description != nil {
+		t.Errorf("unexpected url, title or description %+v", input)
+	}
+	if *input.Replace || *input.Shared || !*input.ToRead || !input.Timestamp.Equal(day) || input.Tags[0] != "go" {
+		t.Errorf("unexpected flags, time or tags %+v", input)
+	}
+
+	bookmark.Extended = "description"
+	bookmark.Time = time.Time{}
+	input = bookmark.PostsAddInput(true)
+	if *input.Description != "description" || input.Timestamp != nil || !*input.Replace {
+		t.Errorf("unexpected input %+v", input)
+	}
+}
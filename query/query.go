@@ -0,0 +1,294 @@
+// Package query implements a Pinboard style search language for bookmarks.
+//
+// A query is a list of terms, all of which must match:
+//
+//	tag:go            the bookmark has the tag (case insensitive)
+//	site:github.com   the bookmark's host is github.com or a subdomain of it
+//	toread:yes        the bookmark is (or with "no", is not) marked to read
+//	shared:no         the bookmark is (or with "yes", is not) private
+//	after:2023-01-01  the bookmark was created at or after the date (or RFC3339 time)
+//	before:2024-01-01 the bookmark was created before the date (or RFC3339 time)
+//	word              the title, description or url contains word (case insensitive)
+//	"exact phrase"    the title, description or url contains the phrase (case insensitive)
+//
+// A leading "-" negates a term, e.g. -tag:old. Values can be quoted, e.g. site:"example.com".
+//
+// Pinboard's posts/all only filters by up to three tags and by date, so Query.PostsAllInput pushes
+// those down to the server and Query.Match evaluates the whole query locally.
+package query
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// maxPushdownTags is the number of tags posts/all can filter by
+const maxPushdownTags = 3
+
+// ErrInvalidQuery is returned by Parse when the query cannot be parsed
+type ErrInvalidQuery struct {
+	Err  error
+	Msg  string
+	Term string
+}
+
+// Error returns the error message
+func (e *ErrInvalidQuery) Error() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = "invalid query"
+	}
+	if e.Term != "" {
+		msg += fmt.Sprintf(" at %q", e.Term)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *ErrInvalidQuery) Unwrap() error {
+	return e.Err
+}
+
+// term is a single condition of a query
+type term struct {
+	// field. the field matched, or "" for text
+	field string
+
+	// negate. whether the term is negated
+	negate bool
+
+	// time. the parsed value of after and before terms
+	time time.Time
+
+	// value. the value matched, lower case except for dates
+	value string
+}
+
+// Query is a parsed query
+type Query struct {
+	// source. the query as given to Parse
+	source string
+
+	// terms. the terms, all of which must match
+	terms []term
+}
+
+// Parse parses a query. An empty query matches every bookmark.
+func Parse(s string) (*Query, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{source: s, terms: []term{}}
+	for _, token := range tokens {
+		t := term{}
+		raw := token.text
+		if !token.quoted && strings.HasPrefix(raw, "-") {
+			t.negate = true
+			raw = raw[1:]
+		}
+		if raw == "" {
+			return nil, &ErrInvalidQuery{Msg: "empty term", Term: token.text}
+		}
+
+		field, value, found := strings.Cut(raw, ":")
+		if token.quoted || !found {
+			t.value = strings.ToLower(unquote(raw))
+			q.terms = append(q.terms, t)
+			continue
+		}
+
+		t.field = strings.ToLower(field)
+		t.value = unquote(value)
+		switch t.field {
+		case "tag", "site":
+			t.value = strings.ToLower(t.value)
+		case "toread", "shared":
+			t.value = strings.ToLower(t.value)
+			if t.value != "yes" && t.value != "no" {
+				return nil, &ErrInvalidQuery{Msg: "expected yes or no", Term: token.text}
+			}
+		case "after", "before":
+			if t.time, err = parseTime(t.value); err != nil {
+				return nil, &ErrInvalidQuery{Msg: "expected a date (2006-01-02) or time (RFC3339)", Term: token.text, Err: err}
+			}
+		default:
+			// not a known field, e.g. a url: match it as text
+			t.field = ""
+			t.value = strings.ToLower(unquote(raw))
+		}
+		if t.value == "" {
+			return nil, &ErrInvalidQuery{Msg: "empty value", Term: token.text}
+		}
+		q.terms = append(q.terms, t)
+	}
+
+	return q, nil
+}
+
+// String returns the query as given to Parse
+func (q *Query) String() string {
+	return q.source
+}
+
+// Match reports whether bookmark matches every term of the query
+func (q *Query) Match(bookmark thumbtack.Bookmark) bool {
+	for _, t := range q.terms {
+		if t.match(bookmark) == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the bookmarks matching the query
+func (q *Query) Filter(bookmarks []thumbtack.Bookmark) []thumbtack.Bookmark {
+	filtered := []thumbtack.Bookmark{}
+	for _, bookmark := range bookmarks {
+		if q.Match(bookmark) {
+			filtered = append(filtered, bookmark)
+		}
+	}
+	return filtered
+}
+
+// PostsAllInput returns the part of the query posts/all can evaluate: up to three tags Pinboard can
+// filter by, and the latest after and earliest before as fromdt and todt. fromdt is a second before the
+// after bound, since posts/all only returns bookmarks created after it. Its results must still be filtered with Match.
+func (q *Query) PostsAllInput() *thumbtack.PostsAllInput {
+	input := &thumbtack.PostsAllInput{}
+	for _, t := range q.terms {
+		if t.negate {
+			continue
+		}
+		switch t.field {
+		case "tag":
+			// a quoted tag with whitespace or commas would be rejected by posts/all; it is only matched locally
+			if len(input.Tags) < maxPushdownTags && thumbtack.Tag(t.value).IsValid() {
+				input.Tags = append(input.Tags, t.value)
+			}
+		case "after":
+			// after is inclusive, fromdt is not
+			if from := t.time.Truncate(time.Second).Add(-time.Second); input.FromDT == nil || from.After(*input.FromDT) {
+				input.FromDT = &from
+			}
+		case "before":
+			if input.ToDT == nil || t.time.Before(*input.ToDT) {
+				to := t.time
+				input.ToDT = &to
+			}
+		}
+	}
+	return input
+}
+
+// Search returns the bookmarks of the account matching the query.
+// The query is pushed down to posts/all as far as possible and the rest is evaluated locally.
+func (q *Query) Search(ctx context.Context, client thumbtack.PostsAPI) ([]thumbtack.Bookmark, error) {
+	bookmarks, err := client.PostsAllWithContext(ctx, q.PostsAllInput())
+	if err != nil {
+		return nil, err
+	}
+	return q.Filter(*bookmarks), nil
+}
+
+// match reports whether bookmark matches the term, ignoring negation
+func (t term) match(bookmark thumbtack.Bookmark) bool {
+	switch t.field {
+	case "tag":
+		return bookmark.HasTags(t.value)
+	case "site":
+		u, err := url.Parse(bookmark.Href)
+		if err != nil {
+			return false
+		}
+		host := strings.ToLower(u.Hostname())
+		return host == t.value || strings.HasSuffix(host, "."+t.value)
+	case "toread":
+		return bookmark.ToRead == (t.value == "yes")
+	case "shared":
+		return bookmark.Shared == (t.value == "yes")
+	case "after":
+		return !bookmark.Time.Before(t.time)
+	case "before":
+		return bookmark.Time.Before(t.time)
+	default:
+		return strings.Contains(strings.ToLower(bookmark.Description), t.value) ||
+			strings.Contains(strings.ToLower(bookmark.Extended), t.value) ||
+			strings.Contains(strings.ToLower(bookmark.Href), t.value)
+	}
+}
+
+// parseTime parses a date or an RFC3339 time
+func parseTime(value string) (time.Time, error) {
+	if timestamp, err := time.Parse(time.DateOnly, value); err == nil {
+		return timestamp, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// token is a whitespace separated part of a query
+type token struct {
+	// quoted. whether the whole token is a quoted phrase
+	quoted bool
+
+	// text. the text of the token, including quotes within it
+	text string
+}
+
+// tokenize splits s at whitespace outside of double quotes
+func tokenize(s string) ([]token, error) {
+	tokens := []token{}
+	current := strings.Builder{}
+	inQuotes, quoted, started := false, false, false
+
+	flush := func() {
+		if started {
+			tokens = append(tokens, token{quoted: quoted, text: current.String()})
+		}
+		current.Reset()
+		inQuotes, quoted, started = false, false, false
+	}
+
+	for _, r := range s {
+		switch {
+		case r == '"':
+			if !started {
+				quoted = true
+			}
+			inQuotes = !inQuotes
+			started = true
+			current.WriteRune(r)
+		case unicode.IsSpace(r) && !inQuotes:
+			flush()
+		default:
+			if quoted && !inQuotes {
+				// text after a closing quote, e.g. "foo"bar
+				quoted = false
+			}
+			started = true
+			current.WriteRune(r)
+		}
+	}
+	if inQuotes {
+		return nil, &ErrInvalidQuery{Msg: "unterminated quote", Term: current.String()}
+	}
+	flush()
+
+	return tokens, nil
+}
+
+// unquote removes double quotes from s
+func unquote(s string) string {
+	return strings.ReplaceAll(s, `"`, "")
+}
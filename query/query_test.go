@@ -0,0 +1,152 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/thumbtacktest"
+)
+
+var day = time.Date(2023, 3, 20, 16, 30, 35, 0, time.UTC)
+
+var bookmarks = []thumbtack.Bookmark{
+	{Href: "https://github.com/rmrfslashbin/thumbtack", Description: "Thumbtack", Extended: "A Go client for Pinboard", Tags: []string{"go", "pinboard"}, Time: day, Shared: true},
+	{Href: "https://gist.github.com/example", Description: "Old gist", Tags: []string{"Go", "old"}, Time: day.AddDate(-1, 0, 0), ToRead: true},
+	{Href: "https://example.com/rfc", Description: "RFC 3339 explained", Tags: []string{"rfc"}, Time: day.AddDate(0, 1, 0), Shared: true, ToRead: true},
+}
+
+// capturingPosts records the input of PostsAll
+type capturingPosts struct {
+	thumbtack.PostsAPI
+
+	// input. the input of the last PostsAll
+	input *thumbtack.PostsAllInput
+}
+
+// PostsAllWithContext records input and passes it on
+func (c *capturingPosts) PostsAllWithContext(ctx context.Context, input *thumbtack.PostsAllInput) (*[]thumbtack.Bookmark, error) {
+	c.input = input
+	return c.PostsAPI.PostsAllWithContext(ctx, input)
+}
+
+// TestMatch tests evaluating queries locally
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"Thumbtack", "Old gist", "RFC 3339 explained"}},
+		{"tag:go", []string{"Thumbtack", "Old gist"}},
+		{"TAG:GO -tag:old", []string{"Thumbtack"}},
+		{"site:github.com", []string{"Thumbtack", "Old gist"}},
+		{"-site:gist.github.com", []string{"Thumbtack", "RFC 3339 explained"}},
+		{"toread:yes", []string{"Old gist", "RFC 3339 explained"}},
+		{"shared:no", []string{"Old gist"}},
+		{"after:2023-03-01", []string{"Thumbtack", "RFC 3339 explained"}},
+		{"after:2023-01-01 before:2023-04-01", []string{"Thumbtack"}},
+		{"before:2023-03-20T16:30:35Z", []string{"Old gist"}},
+		{`"pinboard"`, []string{"Thumbtack"}},
+		{`"go client"`, []string{"Thumbtack"}},
+		{`"client go"`, []string{}},
+		{"rfc explained", []string{"RFC 3339 explained"}},
+		{"-rfc", []string{"Thumbtack", "Old gist"}},
+		{"https://example.com", []string{"RFC 3339 explained"}},
+		{`site:"github.com" tag:pinboard`, []string{"Thumbtack"}},
+	}
+
+	for _, test := range tests {
+		q, err := Parse(test.query)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", test.query, err)
+		}
+		got := []string{}
+		for _, bookmark := range q.Filter(bookmarks) {
+			got = append(got, bookmark.Description)
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("%q: expected %v, got %v", test.query, test.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%q: expected %v, got %v", test.query, test.want, got)
+				break
+			}
+		}
+	}
+}
+
+// TestParseErrors tests that malformed queries are rejected
+func TestParseErrors(t *testing.T) {
+	for _, query := range []string{`"unterminated`, "-", "tag:", "toread:maybe", "after:yesterday", `tag:""`} {
+		invalid := &ErrInvalidQuery{}
+		if _, err := Parse(query); !errors.As(err, &invalid) {
+			t.Errorf("%q: expected ErrInvalidQuery, got %v", query, err)
+		}
+	}
+}
+
+// TestPostsAllInput tests pushing tags and dates down to posts/all
+func TestPostsAllInput(t *testing.T) {
+	q, err := Parse("tag:a -tag:b tag:c after:2023-01-01 after:2023-02-01 tag:d tag:e before:2024-01-01 before:2023-06-01 word")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	input := q.PostsAllInput()
+	if len(input.Tags) != 3 || input.Tags[0] != "a" || input.Tags[1] != "c" || input.Tags[2] != "d" {
+		t.Errorf("expected the first 3 positive tags, got %v", input.Tags)
+	}
+	if input.FromDT == nil || !input.FromDT.Equal(time.Date(2023, 1, 31, 23, 59, 59, 0, time.UTC)) {
+		t.Errorf("expected a second before the latest after as fromdt, got %v", input.FromDT)
+	}
+	if input.ToDT == nil || !input.ToDT.Equal(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the earliest before as todt, got %v", input.ToDT)
+	}
+}
+
+// TestSearch tests searching an account
+func TestSearch(t *testing.T) {
+	srv := thumbtacktest.NewServer("test:abc123")
+	defer srv.Close()
+	for _, bookmark := range bookmarks {
+		srv.AddBookmark(bookmark)
+	}
+
+	client := srv.QuietClient(t)
+
+	q, err := Parse("tag:go -tag:old site:github.com")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	capture := &capturingPosts{PostsAPI: client}
+	found, err := q.Search(context.Background(), capture)
+	if err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+	if len(found) != 1 || found[0].Href != bookmarks[0].Href {
+		t.Errorf("expected thumbtack, got %+v", found)
+	}
+
+	if capture.input == nil || len(capture.input.Tags) != 1 || capture.input.Tags[0] != "go" {
+		t.Errorf("expected the tag to be pushed down, got %+v", capture.input)
+	}
+
+	// a tag posts/all cannot filter by is matched locally instead of failing the search
+	q, err = Parse(`tag:"a b" tag:go`)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	found, err = q.Search(context.Background(), capture)
+	if err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+	if len(found) != 0 {
+		t.Errorf("expected no bookmarks, got %+v", found)
+	}
+	if len(capture.input.Tags) != 1 || capture.input.Tags[0] != "go" {
+		t.Errorf("expected only the valid tag to be pushed down, got %v", capture.input.Tags)
+	}
+}
@@ -0,0 +1,153 @@
+package thumbtack
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Pinboard API rate limits.
+// https://pinboard.in/api/#limits
+const (
+	// DefaultRateLimitInterval is the minimum time between any two API calls
+	DefaultRateLimitInterval = 3 * time.Second
+
+	// PostsAllRateLimitInterval is the minimum time between two posts/all calls
+	PostsAllRateLimitInterval = 5 * time.Minute
+
+	// PostsRecentRateLimitInterval is the minimum time between two posts/recent calls
+	PostsRecentRateLimitInterval = time.Minute
+)
+
+// RateLimiterOption configures a RateLimiter
+type RateLimiterOption func(r *RateLimiter)
+
+// RateLimiter enforces the Pinboard per-endpoint rate limits.
+// A single RateLimiter is safe for concurrent use and may be shared by several goroutines
+// (or several Clients using the same token).
+type RateLimiter struct {
+	// defaultInterval. the minimum time between any two calls
+	defaultInterval time.Duration
+
+	// failFast. if true, calls that would have to wait fail with ErrRateLimitExceeded
+	failFast bool
+
+	// intervals. the minimum time between two calls to the same api, keyed by api name (e.g. "PostsAll")
+	intervals map[string]time.Duration
+
+	// last. the time reserved for the most recent call to any api
+	last time.Time
+
+	// lastByApi. the time reserved for the most recent call to each api
+	lastByApi map[string]time.Time
+
+	// mu. guards last and lastByApi
+	mu sync.Mutex
+}
+
+// NewRateLimiter returns a RateLimiter with the documented Pinboard limits:
+// one call every three seconds, posts/all once every five minutes
+// and posts/recent once every minute.
+func NewRateLimiter(opts ...RateLimiterOption) *RateLimiter {
+	r := &RateLimiter{
+		defaultInterval: DefaultRateLimitInterval,
+		intervals: map[string]time.Duration{
+			"PostsAll":    PostsAllRateLimitInterval,
+			"PostsRecent": PostsRecentRateLimitInterval,
+		},
+		lastByApi: map[string]time.Time{},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// WithRateLimitDefault sets the minimum time between any two calls
+func WithRateLimitDefault(interval time.Duration) RateLimiterOption {
+	return func(r *RateLimiter) {
+		r.defaultInterval = interval
+	}
+}
+
+// WithRateLimitFailFast makes calls fail with ErrRateLimitExceeded instead of blocking
+func WithRateLimitFailFast(failFast bool) RateLimiterOption {
+	return func(r *RateLimiter) {
+		r.failFast = failFast
+	}
+}
+
+// WithRateLimitInterval sets the minimum time between two calls to api (e.g. "PostsAll")
+func WithRateLimitInterval(api string, interval time.Duration) RateLimiterOption {
+	return func(r *RateLimiter) {
+		r.intervals[api] = interval
+	}
+}
+
+// interval returns the minimum time between two calls to api
+func (r *RateLimiter) interval(api string) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	interval := r.defaultInterval
+	if apiInterval, ok := r.intervals[api]; ok && apiInterval > interval {
+		interval = apiInterval
+	}
+	return interval
+}
+
+// Wait blocks until a call to api is allowed or ctx is done.
+// If the limiter is configured to fail fast, ErrRateLimitExceeded is returned
+// instead of blocking. A slot is reserved before waiting, so concurrent callers
+// are served in the order they arrive. If ctx is done first, the slot is released
+// unless a later caller already reserved the next one.
+func (r *RateLimiter) Wait(ctx context.Context, api string) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	r.mu.Lock()
+	now := time.Now()
+	next := now
+	if !r.last.IsZero() && r.last.Add(r.defaultInterval).After(next) {
+		next = r.last.Add(r.defaultInterval)
+	}
+	if interval, ok := r.intervals[api]; ok {
+		if last, ok := r.lastByApi[api]; ok && last.Add(interval).After(next) {
+			next = last.Add(interval)
+		}
+	}
+
+	wait := next.Sub(now)
+	if wait > 0 && r.failFast {
+		r.mu.Unlock()
+		return &ErrRateLimitExceeded{Api: api, Wait: wait}
+	}
+
+	// reserve the slot
+	previous := r.last
+	previousByApi, reservedByApi := r.lastByApi[api]
+	r.last = next
+	r.lastByApi[api] = next
+	r.mu.Unlock()
+
+	if err := sleep(ctx, wait); err != nil {
+		// release the slot so the canceled call does not delay later callers
+		r.mu.Lock()
+		if r.last.Equal(next) {
+			r.last = previous
+		}
+		if r.lastByApi[api].Equal(next) {
+			if reservedByApi {
+				r.lastByApi[api] = previousByApi
+			} else {
+				delete(r.lastByApi, api)
+			}
+		}
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package thumbtack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// TestRateLimiterDefaultInterval tests that consecutive calls are spaced by the default interval
+func TestRateLimiterDefaultInterval(t *testing.T) {
+	limiter := NewRateLimiter(WithRateLimitDefault(50 * time.Millisecond))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.Background(), "TagsGet"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected at least 100ms between three calls, got %s", elapsed)
+	}
+}
+
+// TestRateLimiterApiInterval tests that an api specific interval is enforced
+func TestRateLimiterApiInterval(t *testing.T) {
+	limiter := NewRateLimiter(
+		WithRateLimitDefault(0),
+		WithRateLimitInterval("PostsAll", 100*time.Millisecond),
+	)
+
+	start := time.Now()
+	if err := limiter.Wait(context.Background(), "PostsAll"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// other apis are not held back by the PostsAll interval
+	if err := limiter.Wait(context.Background(), "TagsGet"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("expected TagsGet to not wait, waited %s", elapsed)
+	}
+	if err := limiter.Wait(context.Background(), "PostsAll"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected second PostsAll to wait 100ms, waited %s", elapsed)
+	}
+}
+
+// TestRateLimiterFailFast tests that a fail-fast limiter returns ErrRateLimitExceeded
+func TestRateLimiterFailFast(t *testing.T) {
+	limiter := NewRateLimiter(WithRateLimitFailFast(true))
+
+	if err := limiter.Wait(context.Background(), "PostsAll"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := limiter.Wait(context.Background(), "PostsAll")
+	if e, ok := err.(*ErrRateLimitExceeded); !ok {
+		t.Fatalf("expected ErrRateLimitExceeded, got %T", err)
+	} else if e.Api != "PostsAll" || e.Wait <= 0 {
+		t.Errorf("expected Api PostsAll and a positive Wait, got %q and %s", e.Api, e.Wait)
+	}
+}
+
+// TestRateLimiterContextCanceled tests that waiting stops when the context is canceled
+func TestRateLimiterContextCanceled(t *testing.T) {
+	limiter := NewRateLimiter()
+	if err := limiter.Wait(context.Background(), "TagsGet"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := limiter.Wait(ctx, "TagsGet")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+// TestRateLimiterContextCanceledReleasesSlot tests that a canceled wait does not delay later callers
+func TestRateLimiterContextCanceledReleasesSlot(t *testing.T) {
+	limiter := NewRateLimiter(WithRateLimitDefault(200*time.Millisecond), WithRateLimitInterval("TagsGet", 200*time.Millisecond))
+	start := time.Now()
+	if err := limiter.Wait(context.Background(), "TagsGet"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, "TagsGet"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if err := limiter.Wait(context.Background(), "TagsGet"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 350*time.Millisecond {
+		t.Errorf("expected the canceled slot to be released, waited %s", elapsed)
+	}
+}
+
+// TestRateLimiterConcurrent tests that goroutines sharing a client never exceed the limit
+func TestRateLimiterConcurrent(t *testing.T) {
+	token := "test:abc123"
+	useragent := "test/1.0"
+	interval := 20 * time.Millisecond
+
+	var mu sync.Mutex
+	var calls []time.Time
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, time.Now())
+		mu.Unlock()
+		fmt.Fprint(w, `{"result":"abc123"}`)
+	}))
+	defer ts.Close()
+
+	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(zerolog.PanicLevel)
+	url, _ := url.Parse(ts.URL)
+
+	client, err := New(
+		WithEndpoint(url),
+		WithToken(&token),
+		WithLogger(&log),
+		WithUserAgent(&useragent),
+		WithRateLimiter(NewRateLimiter(WithRateLimitDefault(interval))),
+	)
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.UserSecret(); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(calls) != 5 {
+		t.Fatalf("expected 5 calls, got %d", len(calls))
+	}
+	first, last := calls[0], calls[0]
+	for _, c := range calls {
+		if c.Before(first) {
+			first = c
+		}
+		if c.After(last) {
+			last = c
+		}
+	}
+	// 5 calls need at least 4 intervals; allow a little slack for scheduling
+	if spread := last.Sub(first); spread < 4*interval-5*time.Millisecond {
+		t.Errorf("expected calls to be spread over at least %s, got %s", 4*interval, spread)
+	}
+}
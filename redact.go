@@ -0,0 +1,99 @@
+package thumbtack
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// redacted replaces the secret part of the auth token in logs and errors
+const redacted = "REDACTED"
+
+// redact removes the secret part of the client's auth token from s.
+// The token has the form "username:TOKEN"; the username is kept so log lines remain useful.
+// Both the plain and the url encoded forms of the secret are replaced.
+func (c *Client) redact(s string) string {
+	secret := c.tokenSecret()
+	if secret == "" {
+		return s
+	}
+
+	s = strings.ReplaceAll(s, secret, redacted)
+	if escaped := url.QueryEscape(secret); escaped != secret {
+		s = strings.ReplaceAll(s, escaped, redacted)
+	}
+	return s
+}
+
+// redactError returns err with the auth token removed from its message.
+// *url.Error, as returned by the transport, keeps its type; other errors are wrapped
+// so errors.Is and errors.As still reach the original error.
+func (c *Client) redactError(err error) error {
+	if err == nil || c.tokenSecret() == "" {
+		return err
+	}
+
+	if urlErr, ok := err.(*url.Error); ok {
+		clean := *urlErr
+		clean.URL = c.redact(urlErr.URL)
+		if urlErr.Err != nil && c.redact(urlErr.Err.Error()) != urlErr.Err.Error() {
+			clean.Err = &redactedError{msg: c.redact(urlErr.Err.Error()), err: urlErr.Err}
+		}
+		return &clean
+	}
+
+	if msg := err.Error(); c.redact(msg) != msg {
+		return &redactedError{msg: c.redact(msg), err: err}
+	}
+	return err
+}
+
+// tokenSecret returns the secret part of the auth token
+func (c *Client) tokenSecret() string {
+	if c.token == nil {
+		return ""
+	}
+	token := *c.token
+	if i := strings.LastIndex(token, ":"); i >= 0 {
+		token = token[i+1:]
+	}
+	return token
+}
+
+// String returns a description of the client without the auth token.
+// It keeps the token out of fmt verbs and debug dumps.
+func (c *Client) String() string {
+	token := ""
+	if c.token != nil {
+		token = c.redact(*c.token)
+	}
+	endpoint := ""
+	if c.endpoint != nil {
+		endpoint = c.endpoint.String()
+	}
+	return fmt.Sprintf("thumbtack.Client{endpoint: %q, token: %q}", endpoint, token)
+}
+
+// GoString returns the same as String, for the %#v verb
+func (c *Client) GoString() string {
+	return c.String()
+}
+
+// redactedError is an error whose message had the auth token removed
+type redactedError struct {
+	// msg. the redacted message
+	msg string
+
+	// err. the original error
+	err error
+}
+
+// Error returns the redacted error message
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the original error
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
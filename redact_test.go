@@ -0,0 +1,140 @@
+package thumbtack
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/rs/zerolog"
+)
+
+// callAllMethods calls every client method and returns the errors
+func callAllMethods(client *Client) []error {
+	addUrl := "https://example.com"
+	addTitle := "Example Title"
+	old := "old"
+	new := "new"
+
+	var errs []error
+	collect := func(_ interface{}, err error) {
+		errs = append(errs, err)
+	}
+	collect(client.PostsAdd(&PostsAddInput{Url: &addUrl, Title: &addTitle}))
+	collect(client.PostsAll(nil))
+	collect(client.PostsDates(nil))
+	collect(client.PostsDelete(addUrl))
+	collect(client.PostsGet(nil))
+	collect(client.PostsRecent(nil))
+	collect(client.PostsSuggest(addUrl))
+	collect(client.PostsUpdate())
+	collect(client.NotesById("abc"))
+	collect(client.NotesList())
+	collect(client.TagsDelete(old))
+	collect(client.TagsGet())
+	collect(client.TagsRename(&TagsRenameInput{Old: &old, New: &new}))
+	collect(client.UserSecret())
+	return errs
+}
+
+// TestRedactToken tests that the auth token never shows up in logs, errors or dumps
+func TestRedactToken(t *testing.T) {
+	secret := "ABCDEF0123456789"
+	token := "user:" + secret
+	useragent := "test/1.0"
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	garbage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "garbage")
+	}))
+	defer garbage.Close()
+
+	closed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closed.Close()
+
+	buf := &bytes.Buffer{}
+	log := zerolog.New(buf).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(zerolog.TraceLevel)
+	defer zerolog.SetGlobalLevel(zerolog.PanicLevel)
+
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = 2
+	policy.BaseDelay = time.Millisecond
+
+	for _, endpoint := range []string{failing.URL, garbage.URL, closed.URL} {
+		url, _ := url.Parse(endpoint)
+		client, err := New(
+			WithEndpoint(url),
+			WithToken(&token),
+			WithLogger(&log),
+			WithUserAgent(&useragent),
+			WithRetryPolicy(policy),
+		)
+		if err != nil {
+			t.Fatalf("failed to create thumbtask instance: %v", err)
+		}
+
+		for _, err := range callAllMethods(client) {
+			if err == nil {
+				t.Fatalf("expected error to not be nil")
+			}
+			fmt.Fprintf(buf, "%v\n%+v\n", err, err)
+			for e := err; e != nil; e = errors.Unwrap(e) {
+				if _, ok := e.(*redactedError); ok {
+					break
+				}
+				buf.WriteString(e.Error() + "\n")
+			}
+		}
+
+		fmt.Fprintf(buf, "%v\n%+v\n%#v\n%s\n", client, client, client, client)
+		buf.WriteString(spew.Sdump(client))
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "calling endpoint") {
+		t.Fatalf("expected debug output to be captured")
+	}
+	if strings.Contains(output, secret) {
+		t.Errorf("expected the token to be redacted, found it in:\n%s", output)
+	}
+	if !strings.Contains(output, redacted) {
+		t.Errorf("expected output to contain %s", redacted)
+	}
+}
+
+// TestRedactErrorKeepsType tests that redacted errors can still be inspected
+func TestRedactErrorKeepsType(t *testing.T) {
+	token := "user:SECRET"
+	client := &Client{token: &token}
+
+	urlErr := &url.Error{Op: "Get", URL: "https://example.com/?auth_token=user%3ASECRET", Err: errors.New("boom SECRET")}
+	err := client.redactError(urlErr)
+	if _, ok := err.(*url.Error); !ok {
+		t.Fatalf("expected *url.Error, got %T", err)
+	}
+	if strings.Contains(err.Error(), "SECRET") {
+		t.Errorf("expected token to be redacted, got %s", err.Error())
+	}
+	if !errors.Is(err, urlErr.Err) {
+		t.Errorf("expected redacted error to wrap the original error")
+	}
+	if urlErr.URL != "https://example.com/?auth_token=user%3ASECRET" {
+		t.Errorf("expected the original error to not be modified")
+	}
+
+	plain := errors.New("no token here")
+	if client.redactError(plain) != plain {
+		t.Errorf("expected errors without the token to be returned as is")
+	}
+}
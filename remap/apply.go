@@ -0,0 +1,139 @@
+package remap
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"os"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// ApplyOption configures Apply
+type ApplyOption func(a *applier)
+
+// applier holds the Apply options
+type applier struct {
+	// journal. the path of the journal file, if any
+	journal string
+
+	// merge. whether conflicting steps are applied
+	merge bool
+
+	// progress. called after each step
+	progress func(done int, total int)
+}
+
+// WithJournal records every applied rename in the file at path, one old,new row per rename.
+// ReadJournal reads it back for WithApplied, so a failed run can be resumed.
+func WithJournal(path string) ApplyOption {
+	return func(a *applier) {
+		a.journal = path
+	}
+}
+
+// WithMerge sets whether steps that rename onto a tag already in the account are applied, merging the two.
+// Default is false; Apply then returns ErrConflict before renaming anything.
+func WithMerge(merge bool) ApplyOption {
+	return func(a *applier) {
+		a.merge = merge
+	}
+}
+
+// WithProgress sets a function called after each step with the number of steps done and the total
+func WithProgress(progress func(done int, total int)) ApplyOption {
+	return func(a *applier) {
+		a.progress = progress
+	}
+}
+
+// ApplyResult counts the steps handled by Apply
+type ApplyResult struct {
+	// Renamed is the number of tags renamed to a new tag
+	Renamed int `json:"renamed"`
+
+	// Merged is the number of tags merged into an existing tag
+	Merged int `json:"merged"`
+
+	// Resumed is the number of steps skipped because an earlier run applied them
+	Resumed int `json:"resumed"`
+
+	// Skipped is the number of steps skipped because the old tag does not exist
+	Skipped int `json:"skipped"`
+}
+
+// Apply runs the steps of plan in order with TagsRename. A client with a rate limiter spaces the calls.
+// It stops at the first error, returning the result so far along with it.
+func Apply(ctx context.Context, client thumbtack.TagsAPI, plan *Plan, opts ...ApplyOption) (*ApplyResult, error) {
+	a := &applier{}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	result := &ApplyResult{}
+
+	if !a.merge {
+		if conflicts := plan.Conflicts(); len(conflicts) > 0 {
+			return result, &ErrConflict{Rename: conflicts[0].Rename}
+		}
+	}
+
+	var journal *csv.Writer
+	if a.journal != "" {
+		file, err := os.OpenFile(a.journal, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return result, err
+		}
+		defer file.Close()
+		journal = csv.NewWriter(file)
+	}
+
+	for n, step := range plan.Steps {
+		switch step.Action {
+		case ActionDone:
+			result.Resumed++
+		case ActionSkip:
+			result.Skipped++
+		default:
+			old, new := step.Old, step.New
+			if _, err := client.TagsRenameWithContext(ctx, &thumbtack.TagsRenameInput{Old: &old, New: &new}); err != nil {
+				return result, err
+			}
+			if step.Action == ActionMerge {
+				result.Merged++
+			} else {
+				result.Renamed++
+			}
+
+			if journal != nil {
+				if err := journal.Write([]string{step.Old, step.New}); err != nil {
+					return result, err
+				}
+				journal.Flush()
+				if err := journal.Error(); err != nil {
+					return result, err
+				}
+			}
+		}
+
+		if a.progress != nil {
+			a.progress(n+1, len(plan.Steps))
+		}
+	}
+
+	return result, nil
+}
+
+// ReadJournal returns the renames recorded by WithJournal. A missing journal records none.
+// Unlike ReadMapping, every row is a rename, including one renaming the tag old to new.
+func ReadJournal(path string) (Mapping, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Mapping{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return readRenames(csv.NewReader(file), false)
+}
@@ -0,0 +1,136 @@
+package remap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/thumbtacktest"
+)
+
+// newTestServer returns a fake server with tagged bookmarks and a client for it
+func newTestServer(t *testing.T) (*thumbtacktest.Server, *thumbtack.Client) {
+	srv := thumbtacktest.NewServer("test:abc123")
+	t.Cleanup(srv.Close)
+	srv.AddBookmark(thumbtack.Bookmark{Href: "https://go.dev", Description: "Go", Tags: []string{"golang", "go"}})
+	srv.AddBookmark(thumbtack.Bookmark{Href: "https://example.com", Description: "Example", Tags: []string{"Golang", "js"}})
+	return srv, srv.QuietClient(t)
+}
+
+// tagsOf returns the sorted tags of every bookmark on srv, by url
+func tagsOf(srv *thumbtacktest.Server) map[string][]string {
+	tags := map[string][]string{}
+	for _, bookmark := range srv.Bookmarks() {
+		sorted := append([]string{}, bookmark.Tags...)
+		sort.Strings(sorted)
+		tags[bookmark.Href] = sorted
+	}
+	return tags
+}
+
+// TestApply tests applying a plan, and that conflicts need merging to be allowed
+func TestApply(t *testing.T) {
+	srv, client := newTestServer(t)
+	tags, err := client.TagsGet()
+	if err != nil {
+		t.Fatalf("failed to get tags: %v", err)
+	}
+	plan, err := NewPlan(Mapping{{Old: "js", New: "javascript"}, {Old: "golang", New: "go"}, {Old: "missing", New: "found"}}, tags)
+	if err != nil {
+		t.Fatalf("failed to plan: %v", err)
+	}
+
+	conflict := &ErrConflict{}
+	if _, err := Apply(context.Background(), client, plan); !errors.As(err, &conflict) || conflict.Rename.Old != "golang" {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if tagsOf(srv)["https://example.com"][1] != "js" {
+		t.Fatalf("expected no tag to be renamed after a conflict, got %v", tagsOf(srv))
+	}
+
+	result, err := Apply(context.Background(), client, plan, WithMerge(true))
+	if err != nil {
+		t.Fatalf("failed to apply: %v", err)
+	}
+	if *result != (ApplyResult{Renamed: 1, Merged: 1, Skipped: 1}) {
+		t.Errorf("unexpected result %+v", result)
+	}
+	want := map[string][]string{"https://go.dev": {"go"}, "https://example.com": {"go", "javascript"}}
+	if got := tagsOf(srv); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestApplyResume tests that a failed run resumes from its journal
+func TestApplyResume(t *testing.T) {
+	srv, client := newTestServer(t)
+	mapping := Mapping{{Old: "js", New: "javascript"}, {Old: "Golang", New: "lang-go"}, {Old: "go", New: "lang-go"}}
+	journal := filepath.Join(t.TempDir(), "mapping.csv.journal")
+
+	tags, _ := client.TagsGet()
+	plan, err := NewPlan(mapping, tags)
+	if err != nil {
+		t.Fatalf("failed to plan: %v", err)
+	}
+	srv.FailNextMatching("/tags/rename", "old", "go", http.StatusInternalServerError)
+	result, err := Apply(context.Background(), client, plan, WithJournal(journal))
+	if err == nil || result.Renamed != 2 {
+		t.Fatalf("expected the run to fail after 2 renames, got %+v, %v", result, err)
+	}
+
+	applied, err := ReadJournal(journal)
+	if err != nil || len(applied) != 2 {
+		t.Fatalf("expected 2 renames in the journal, got %v, %v", applied, err)
+	}
+	tags, _ = client.TagsGet()
+	plan, err = NewPlan(mapping, tags, WithApplied(applied))
+	if err != nil {
+		t.Fatalf("failed to plan: %v", err)
+	}
+	if len(plan.Conflicts()) != 0 {
+		t.Errorf("expected no conflicts when resuming, got %+v", plan.Conflicts())
+	}
+
+	progress := 0
+	result, err = Apply(context.Background(), client, plan, WithJournal(journal), WithProgress(func(done int, total int) {
+		progress = done
+	}))
+	if err != nil {
+		t.Fatalf("failed to resume: %v", err)
+	}
+	if *result != (ApplyResult{Merged: 1, Resumed: 2}) || progress != 3 {
+		t.Errorf("unexpected result %+v after %d steps", result, progress)
+	}
+	want := map[string][]string{"https://go.dev": {"lang-go"}, "https://example.com": {"javascript", "lang-go"}}
+	if got := tagsOf(srv); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestReadJournal tests that every journal row is a rename, including ones that look like a header or a comment
+func TestReadJournal(t *testing.T) {
+	journal := filepath.Join(t.TempDir(), "mapping.csv.journal")
+	if err := os.WriteFile(journal, []byte("old,new\n#go,golang\n"), 0o600); err != nil {
+		t.Fatalf("failed to write journal: %v", err)
+	}
+
+	applied, err := ReadJournal(journal)
+	if err != nil {
+		t.Fatalf("failed to read journal: %v", err)
+	}
+	want := Mapping{{Old: "old", New: "new"}, {Old: "#go", New: "golang"}}
+	if !reflect.DeepEqual(applied, want) {
+		t.Errorf("expected %v, got %v", want, applied)
+	}
+
+	applied, err = ReadJournal(filepath.Join(t.TempDir(), "missing.journal"))
+	if err != nil || len(applied) != 0 {
+		t.Errorf("expected a missing journal to record no renames, got %v, %v", applied, err)
+	}
+}
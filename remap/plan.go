@@ -0,0 +1,232 @@
+package remap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// Action is what a step of a plan does
+type Action string
+
+const (
+	// ActionRename renames a tag to a tag that does not exist
+	ActionRename Action = "rename"
+
+	// ActionMerge renames a tag onto a tag that exists, merging the two
+	ActionMerge Action = "merge"
+
+	// ActionSkip skips a rename because the old tag does not exist
+	ActionSkip Action = "skip"
+
+	// ActionDone skips a rename because the journal records it as applied
+	ActionDone Action = "done"
+)
+
+// ErrCycle is returned by NewPlan when the renames of a mapping form a cycle, e.g. a -> b and b -> a
+type ErrCycle struct {
+	Msg  string
+	Tags []string
+}
+
+// Error returns the error message
+func (e *ErrCycle) Error() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = "mapping has a cycle"
+	}
+	return msg + ": " + strings.Join(e.Tags, " -> ")
+}
+
+// ErrConflict is returned by Apply when a step renames onto a tag that already exists in the account
+// and merging was not allowed
+type ErrConflict struct {
+	Msg    string
+	Rename Rename
+}
+
+// Error returns the error message
+func (e *ErrConflict) Error() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = "tag already exists"
+	}
+	return fmt.Sprintf("%s: %s", msg, e.Rename)
+}
+
+// Step is a rename of a plan
+type Step struct {
+	Rename
+
+	// Action is what the step does
+	Action Action `json:"action"`
+
+	// Count is the number of bookmarks with the old tag, according to TagsGet and the earlier steps
+	Count int `json:"count"`
+
+	// Conflict is true if the step merges into a tag that already exists in the account,
+	// rather than one created by an earlier step
+	Conflict bool `json:"conflict"`
+}
+
+// Plan is a mapping checked against the tags of an account
+type Plan struct {
+	// Steps are the renames, in order
+	Steps []Step `json:"steps"`
+}
+
+// Conflicts returns the steps that merge into a tag already in the account
+func (p *Plan) Conflicts() []Step {
+	conflicts := []Step{}
+	for _, step := range p.Steps {
+		if step.Conflict {
+			conflicts = append(conflicts, step)
+		}
+	}
+	return conflicts
+}
+
+// PlanOption configures NewPlan
+type PlanOption func(p *planner)
+
+// planner holds the NewPlan options
+type planner struct {
+	// applied. the renames already applied
+	applied map[Rename]bool
+}
+
+// WithApplied marks the renames already applied by an earlier run, as read by ReadJournal
+func WithApplied(applied Mapping) PlanOption {
+	return func(p *planner) {
+		for _, rename := range applied {
+			p.applied[rename] = true
+		}
+	}
+}
+
+// tagState is a tag while a plan is simulated
+type tagState struct {
+	// count. the number of bookmarks with the tag
+	count int
+
+	// original. whether the tag was in the account before the mapping
+	original bool
+}
+
+// NewPlan checks mapping against tags, as returned by TagsGet, and simulates it in order.
+// Tags are compared case insensitively, like Pinboard does.
+func NewPlan(mapping Mapping, tags *thumbtack.Tags, opts ...PlanOption) (*Plan, error) {
+	p := &planner{applied: map[Rename]bool{}}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if cycle := findCycle(mapping); cycle != nil {
+		return nil, &ErrCycle{Tags: cycle}
+	}
+
+	current := map[string]*tagState{}
+	if tags != nil {
+		for tag, count := range tags.Tags {
+			key := strings.ToLower(tag)
+			if current[key] == nil {
+				current[key] = &tagState{original: true}
+			}
+			current[key].count += count
+		}
+	}
+
+	plan := &Plan{Steps: make([]Step, 0, len(mapping))}
+	for _, rename := range mapping {
+		step := Step{Rename: rename}
+		oldKey, newKey := strings.ToLower(rename.Old), strings.ToLower(rename.New)
+		src, dst := current[oldKey], current[newKey]
+
+		switch {
+		case p.applied[rename]:
+			step.Action = ActionDone
+			if dst != nil {
+				// created by this mapping in the earlier run
+				dst.original = false
+			}
+		case src == nil:
+			step.Action = ActionSkip
+		case oldKey == newKey:
+			// only the case changes
+			step.Action = ActionRename
+			step.Count = src.count
+		case dst == nil:
+			step.Action = ActionRename
+			step.Count = src.count
+			current[newKey] = &tagState{count: src.count}
+			delete(current, oldKey)
+		default:
+			step.Action = ActionMerge
+			step.Count = src.count
+			step.Conflict = dst.original
+			dst.count += src.count
+			delete(current, oldKey)
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+
+	return plan, nil
+}
+
+// findCycle returns the tags of the first cycle of renames in mapping, ignoring renames that only change case,
+// or nil if there is none
+func findCycle(mapping Mapping) []string {
+	edges := map[string][]string{}
+	order := []string{}
+	for _, rename := range mapping {
+		from, to := strings.ToLower(rename.Old), strings.ToLower(rename.New)
+		if from == to {
+			continue
+		}
+		if _, ok := edges[from]; !ok {
+			order = append(order, from)
+		}
+		edges[from] = append(edges[from], to)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	path := []string{}
+
+	var visit func(tag string) []string
+	visit = func(tag string) []string {
+		state[tag] = visiting
+		path = append(path, tag)
+		for _, next := range edges[tag] {
+			switch state[next] {
+			case visiting:
+				for i, t := range path {
+					if t == next {
+						return append(append([]string{}, path[i:]...), next)
+					}
+				}
+			case unvisited:
+				if cycle := visit(next); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[tag] = visited
+		return nil
+	}
+
+	for _, tag := range order {
+		if state[tag] == unvisited {
+			if cycle := visit(tag); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
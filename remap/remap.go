@@ -0,0 +1,134 @@
+// Package remap applies many tag renames and merges to an account from a mapping file.
+//
+// A mapping is a CSV file of old,new pairs applied in order with TagsRename. Several rows with the
+// same new tag merge their old tags into it:
+//
+//	# old,new
+//	Golang,go
+//	golang,go
+//	go-lang,go
+//	js,javascript
+//
+// NewPlan checks a mapping against the tags of the account before anything is changed: it rejects
+// cycles, skips tags that do not exist and reports conflicts, i.e. renames onto a tag that already exists.
+// Apply then runs the plan, recording every rename in a journal so a partially applied run can resume:
+//
+//	mapping, err := remap.ReadMapping(file)
+//	tags, err := client.TagsGet()
+//	applied, err := remap.ReadJournal("mapping.csv.journal")
+//	plan, err := remap.NewPlan(mapping, tags, remap.WithApplied(applied))
+//	result, err := remap.Apply(ctx, client, plan, remap.WithJournal("mapping.csv.journal"))
+package remap
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// ErrMapping is returned by ReadMapping when a row of the mapping is not valid
+type ErrMapping struct {
+	Err  error
+	Line int
+	Msg  string
+}
+
+// Error returns the error message
+func (e *ErrMapping) Error() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = "invalid mapping"
+	}
+	if e.Line > 0 {
+		msg += fmt.Sprintf(" on line %d", e.Line)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *ErrMapping) Unwrap() error {
+	return e.Err
+}
+
+// Rename is a row of a mapping: the tag Old is renamed to New
+type Rename struct {
+	// Old is the tag to rename
+	Old string `json:"old"`
+
+	// New is the new name of the tag
+	New string `json:"new"`
+}
+
+// String returns the rename as old -> new
+func (r Rename) String() string {
+	return r.Old + " -> " + r.New
+}
+
+// Mapping is a list of renames, applied in order
+type Mapping []Rename
+
+// ReadMapping reads a mapping from CSV. Blank lines, lines starting with # and an old,new header are skipped.
+func ReadMapping(r io.Reader) (Mapping, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	return readRenames(reader, true)
+}
+
+// readRenames reads old,new rows from reader, skipping an old,new header if header is set
+func readRenames(reader *csv.Reader, header bool) (Mapping, error) {
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	mapping := Mapping{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			return nil, &ErrMapping{Line: line, Err: err}
+		}
+		if len(record) != 2 {
+			return nil, &ErrMapping{Msg: fmt.Sprintf("expected old,new but got %d fields", len(record)), Line: line}
+		}
+
+		rename := Rename{Old: strings.TrimSpace(record[0]), New: strings.TrimSpace(record[1])}
+		if header && len(mapping) == 0 && strings.EqualFold(rename.Old, "old") && strings.EqualFold(rename.New, "new") {
+			continue
+		}
+		for _, tag := range []string{rename.Old, rename.New} {
+			if tag == "" {
+				return nil, &ErrMapping{Msg: "empty tag", Line: line}
+			}
+			if !thumbtack.Tag(tag).IsValid() {
+				return nil, &ErrMapping{Msg: fmt.Sprintf("tag %q must not contain whitespace or commas", tag), Line: line}
+			}
+		}
+		mapping = append(mapping, rename)
+	}
+
+	return mapping, nil
+}
+
+// Write writes the mapping as CSV with an old,new header, as read by ReadMapping
+func (m Mapping) Write(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"old", "new"}); err != nil {
+		return err
+	}
+	for _, rename := range m {
+		if err := writer.Write([]string{rename.Old, rename.New}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
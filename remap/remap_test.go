@@ -0,0 +1,94 @@
+package remap
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// TestReadMapping tests reading and writing mappings
+func TestReadMapping(t *testing.T) {
+	mapping, err := ReadMapping(strings.NewReader("old,new\n# languages\nGolang, go\n\ngo-lang,go\n"))
+	if err != nil {
+		t.Fatalf("failed to read mapping: %v", err)
+	}
+	want := Mapping{{Old: "Golang", New: "go"}, {Old: "go-lang", New: "go"}}
+	if !reflect.DeepEqual(mapping, want) {
+		t.Errorf("expected %v, got %v", want, mapping)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := mapping.Write(buf); err != nil {
+		t.Fatalf("failed to write mapping: %v", err)
+	}
+	read, err := ReadMapping(buf)
+	if err != nil || !reflect.DeepEqual(read, mapping) {
+		t.Errorf("expected %v after a round trip, got %v (%v)", mapping, read, err)
+	}
+}
+
+// TestReadMappingErrors tests that malformed mappings are rejected with their line
+func TestReadMappingErrors(t *testing.T) {
+	for _, input := range []string{"a,b\nc\n", "a,b\nc,\n", "a,b\nc,d e\n", "a,b\nc,d,e\n"} {
+		invalid := &ErrMapping{}
+		if _, err := ReadMapping(strings.NewReader(input)); !errors.As(err, &invalid) || invalid.Line != 2 {
+			t.Errorf("%q: expected ErrMapping on line 2, got %v", input, err)
+		}
+	}
+}
+
+// TestNewPlan tests simulating a mapping against the tags of an account
+func TestNewPlan(t *testing.T) {
+	tags := &thumbtack.Tags{Tags: map[string]int{"Golang": 2, "golang": 1, "go": 5, "js": 3, "Rust": 1}}
+	mapping := Mapping{
+		{Old: "js", New: "javascript"},
+		{Old: "ecmascript", New: "javascript"},
+		{Old: "golang", New: "go"},
+		{Old: "Rust", New: "rust"},
+		{Old: "node", New: "javascript"},
+	}
+
+	plan, err := NewPlan(mapping, tags, WithApplied(Mapping{{Old: "node", New: "javascript"}}))
+	if err != nil {
+		t.Fatalf("failed to plan: %v", err)
+	}
+	want := []Step{
+		{Rename: mapping[0], Action: ActionRename, Count: 3},
+		{Rename: mapping[1], Action: ActionSkip},
+		{Rename: mapping[2], Action: ActionMerge, Count: 3, Conflict: true},
+		{Rename: mapping[3], Action: ActionRename, Count: 1},
+		{Rename: mapping[4], Action: ActionDone},
+	}
+	if !reflect.DeepEqual(plan.Steps, want) {
+		t.Errorf("expected %+v, got %+v", want, plan.Steps)
+	}
+	if conflicts := plan.Conflicts(); len(conflicts) != 1 || conflicts[0].Old != "golang" {
+		t.Errorf("expected the golang merge to conflict, got %+v", conflicts)
+	}
+
+	// merging into a tag created by an earlier step is not a conflict
+	plan, err = NewPlan(Mapping{{Old: "js", New: "javascript"}, {Old: "Rust", New: "javascript"}}, tags)
+	if err != nil {
+		t.Fatalf("failed to plan: %v", err)
+	}
+	if step := plan.Steps[1]; step.Action != ActionMerge || step.Conflict {
+		t.Errorf("expected a merge without conflict, got %+v", step)
+	}
+}
+
+// TestNewPlanCycle tests that cycles are rejected
+func TestNewPlanCycle(t *testing.T) {
+	mapping := Mapping{{Old: "a", New: "b"}, {Old: "Go", New: "go"}, {Old: "b", New: "c"}, {Old: "C", New: "a"}}
+	cycle := &ErrCycle{}
+	if _, err := NewPlan(mapping, nil); !errors.As(err, &cycle) || strings.Join(cycle.Tags, " ") != "a b c a" {
+		t.Errorf("expected the cycle a b c a, got %v", err)
+	}
+
+	if _, err := NewPlan(mapping[:3], nil); err != nil {
+		t.Errorf("expected a chain without a cycle to plan, got %v", err)
+	}
+}
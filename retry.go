@@ -0,0 +1,189 @@
+package thumbtack
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed calls are retried.
+// The zero value never retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values less than 2 disable retries.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. The delay doubles with every further retry.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the backoff delay. Zero means no cap.
+	// MaxDelay does not cap a server provided Retry-After.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction (0 to 1) of each delay that is randomized to spread out retries.
+	Jitter float64
+
+	// RetryAfter honors the Retry-After header of the response when it asks for a longer delay.
+	RetryAfter bool
+
+	// RetryableStatusCodes are the http status codes that are retried.
+	RetryableStatusCodes []int
+
+	// RetryNetworkErrors retries errors returned by the transport (connection refused, reset, timeouts, ...).
+	// Cancellation of the call's context, a fail-fast RateLimiter refusing the call (ErrRateLimitExceeded)
+	// and errors building the request are never retried.
+	RetryNetworkErrors bool
+
+	// IsRetryableError, if set, decides whether a transport error is retried, overriding RetryNetworkErrors.
+	IsRetryableError func(err error) bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy following the Pinboard API guidance:
+// back off on 429 Too Many Requests and server errors by doubling the interval between requests.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   DefaultRateLimitInterval,
+		MaxDelay:    time.Minute,
+		Jitter:      0.2,
+		RetryAfter:  true,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryNetworkErrors: true,
+	}
+}
+
+// WithRetryPolicy sets the retry policy for the controller
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// attempts returns the total number of attempts allowed by the policy
+func (p *RetryPolicy) attempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// backoff returns the delay before retry number attempt (starting at 1)
+func (p *RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+	}
+
+	if p.RetryAfter && retryAfter > delay {
+		delay = retryAfter
+	}
+	return delay
+}
+
+// retryable reports whether err should be retried
+func (p *RetryPolicy) retryable(err error) bool {
+	if p == nil || err == nil {
+		return false
+	}
+
+	var canceled *ErrRequestCanceled
+	if errors.As(err, &canceled) {
+		return false
+	}
+
+	// a fail-fast limiter refused the call; retrying would only hide that from the caller
+	var limited *ErrRateLimitExceeded
+	if errors.As(err, &limited) {
+		return false
+	}
+
+	var status *ErrBadStatusCode
+	if errors.As(err, &status) {
+		for _, code := range p.RetryableStatusCodes {
+			if code == status.StatusCode {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !isNetworkError(err) {
+		return false
+	}
+	if p.IsRetryableError != nil {
+		return p.IsRetryableError(err)
+	}
+	return p.RetryNetworkErrors
+}
+
+// isNetworkError reports whether err was returned by the transport
+func isNetworkError(err error) bool {
+	var endpoint *ErrBadEndpoint
+	if errors.As(err, &endpoint) {
+		// the request could not be built
+		return false
+	}
+
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an http date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return &ErrRequestCanceled{Err: ctx.Err()}
+	case <-timer.C:
+		return nil
+	}
+}
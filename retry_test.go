@@ -0,0 +1,268 @@
+package thumbtack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// newRetryTestClient returns a client for ts with a fast retry policy
+func newRetryTestClient(t *testing.T, endpoint string, policy *RetryPolicy, opts ...Option) *Client {
+	token := "test:abc123"
+	useragent := "test/1.0"
+
+	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(zerolog.PanicLevel)
+	url, _ := url.Parse(endpoint)
+
+	client, err := New(append([]Option{
+		WithEndpoint(url),
+		WithToken(&token),
+		WithLogger(&log),
+		WithUserAgent(&useragent),
+		WithRetryPolicy(policy),
+	}, opts...)...)
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+	return client
+}
+
+// fastRetryPolicy returns the default policy with millisecond delays
+func fastRetryPolicy() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = 3
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 10 * time.Millisecond
+	return policy
+}
+
+// TestRetrySucceeds tests that a call succeeds after transient server errors
+func TestRetrySucceeds(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"result":"abc123"}`)
+	}))
+	defer ts.Close()
+
+	client := newRetryTestClient(t, ts.URL, fastRetryPolicy())
+	secret, err := client.UserSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if secret.Result != "abc123" {
+		t.Errorf("expected result 'abc123', got '%s'", secret.Result)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+// TestRetryExhausted tests that the final error reports the number of attempts
+func TestRetryExhausted(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	client := newRetryTestClient(t, ts.URL, fastRetryPolicy())
+	_, err := client.TagsGet()
+
+	var exhausted *ErrRetriesExhausted
+	if !errors.As(err, &exhausted) {
+		t.Fatalf("expected ErrRetriesExhausted, got %T", err)
+	}
+	if exhausted.Attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", exhausted.Attempts)
+	}
+	var status *ErrBadStatusCode
+	if !errors.As(err, &status) || status.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected wrapped ErrBadStatusCode 429, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+// TestRetryNotRetryable tests that status codes outside the policy are not retried
+func TestRetryNotRetryable(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	client := newRetryTestClient(t, ts.URL, fastRetryPolicy())
+	_, err := client.TagsGet()
+	if _, ok := err.(*ErrBadStatusCode); !ok {
+		t.Fatalf("expected ErrBadStatusCode, got %T", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+// TestRetryNotRetryableAfterRetries tests that an error that is not retried still reports the attempts made
+func TestRetryNotRetryableAfterRetries(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	client := newRetryTestClient(t, ts.URL, fastRetryPolicy())
+	_, err := client.TagsGet()
+	var exhausted *ErrRetriesExhausted
+	if !errors.As(err, &exhausted) || exhausted.Attempts != 2 {
+		t.Fatalf("expected ErrRetriesExhausted after 2 attempts, got %v", err)
+	}
+	if StatusCodeOf(err) != http.StatusNotFound {
+		t.Errorf("expected status code 404, got %d", StatusCodeOf(err))
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+// TestRetryRateLimitFailFast tests that a fail-fast limiter refusing a call is not retried
+func TestRetryRateLimitFailFast(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		fmt.Fprint(w, `{"result":"abc123"}`)
+	}))
+	defer ts.Close()
+
+	limiter := NewRateLimiter(WithRateLimitFailFast(true))
+	client := newRetryTestClient(t, ts.URL, DefaultRetryPolicy(), WithRateLimiter(limiter))
+	if _, err := client.UserSecret(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	start := time.Now()
+	_, err := client.UserSecret()
+	if _, ok := err.(*ErrRateLimitExceeded); !ok {
+		t.Fatalf("expected ErrRateLimitExceeded, got %T: %v", err, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the call to fail on the first attempt, took %s", elapsed)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+// TestRetryBadRequest tests that errors building the request are not retried
+func TestRetryBadRequest(t *testing.T) {
+	client := newRetryTestClient(t, "http://example.com", fastRetryPolicy())
+	_, err := client.callEndpoint(context.Background(), "TagsGet", "/\x7f", "")
+	if _, ok := err.(*ErrBadEndpoint); !ok {
+		t.Fatalf("expected ErrBadEndpoint, got %T: %v", err, err)
+	}
+}
+
+// TestRetryNetworkError tests that transport errors are retried
+func TestRetryNetworkError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	endpoint := ts.URL
+	ts.Close()
+
+	client := newRetryTestClient(t, endpoint, fastRetryPolicy())
+	_, err := client.TagsGet()
+	var exhausted *ErrRetriesExhausted
+	if !errors.As(err, &exhausted) || exhausted.Attempts != 3 {
+		t.Fatalf("expected ErrRetriesExhausted after 3 attempts, got %v", err)
+	}
+
+	policy := fastRetryPolicy()
+	policy.RetryNetworkErrors = false
+	client = newRetryTestClient(t, endpoint, policy)
+	_, err = client.TagsGet()
+	if errors.As(err, &exhausted) {
+		t.Fatalf("expected network error to not be retried, got %v", err)
+	}
+}
+
+// TestRetryAfter tests that the Retry-After header is honored
+func TestRetryAfter(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"result":"abc123"}`)
+	}))
+	defer ts.Close()
+
+	start := time.Now()
+	client := newRetryTestClient(t, ts.URL, fastRetryPolicy())
+	if _, err := client.UserSecret(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected Retry-After of 1s to be honored, took %s", elapsed)
+	}
+}
+
+// TestRetryBackoff tests the exponential backoff and its cap
+func TestRetryBackoff(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, want := range expected {
+		if got := policy.backoff(i+1, 0); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", i+1, got, want)
+		}
+	}
+
+	policy.RetryAfter = true
+	if got := policy.backoff(1, 30*time.Second); got != 30*time.Second {
+		t.Errorf("expected Retry-After to override backoff, got %s", got)
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(2, 0); got < time.Second || got > 2*time.Second {
+			t.Fatalf("expected jittered delay between 1s and 2s, got %s", got)
+		}
+	}
+}
+
+// TestParseRetryAfter tests parsing of the Retry-After header
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"":                              0,
+		"120":                           2 * time.Minute,
+		"-1":                            0,
+		"garbage":                       0,
+		"Mon, 20 Mar 2023 12:00:30 GMT": 30 * time.Second,
+		"Mon, 20 Mar 2023 11:00:00 GMT": 0,
+	}
+	for value, want := range tests {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
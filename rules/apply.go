@@ -0,0 +1,149 @@
+package rules
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// target is what rules look at and change: a bookmark, or the input of PostsAdd
+type target struct {
+	// href. the url
+	href string
+
+	// title. the title
+	title string
+
+	// description. the description (extended)
+	description string
+
+	// tags. the tags, changed by the rules
+	tags []string
+
+	// shared. whether the bookmark is public, changed by the rules
+	shared *bool
+
+	// toread. whether the bookmark is marked to read, changed by the rules
+	toread *bool
+}
+
+// Apply runs the rules on input, changing its tags and shared and toread flags,
+// and returns the names of the rules applied
+func (s *RuleSet) Apply(input *thumbtack.PostsAddInput) []string {
+	if input == nil {
+		return []string{}
+	}
+
+	t := &target{tags: append([]string{}, input.Tags...), shared: input.Shared, toread: input.ToRead}
+	if input.Url != nil {
+		t.href = *input.Url
+	}
+	if input.Title != nil {
+		t.title = *input.Title
+	}
+	if input.Description != nil {
+		t.description = *input.Description
+	}
+
+	applied := s.apply(t)
+	input.Tags, input.Shared, input.ToRead = t.tags, t.shared, t.toread
+	return applied
+}
+
+// ApplyBookmark returns bookmark with the rules applied, and the names of the rules applied
+func (s *RuleSet) ApplyBookmark(bookmark thumbtack.Bookmark) (thumbtack.Bookmark, []string) {
+	shared, toread := bookmark.Shared, bookmark.ToRead
+	t := &target{
+		href:        bookmark.Href,
+		title:       bookmark.Description,
+		description: bookmark.Extended,
+		tags:        append([]string{}, bookmark.Tags...),
+		shared:      &shared,
+		toread:      &toread,
+	}
+
+	applied := s.apply(t)
+	bookmark.Tags, bookmark.Shared, bookmark.ToRead = t.tags, *t.shared, *t.toread
+	return bookmark, applied
+}
+
+// apply runs the rules on t in order and returns the names of the rules applied
+func (s *RuleSet) apply(t *target) []string {
+	applied := []string{}
+	for n, rule := range s.Rules {
+		if !rule.Match.matches(t) {
+			continue
+		}
+		for _, tag := range rule.Add {
+			if !hasTag(t.tags, tag) {
+				t.tags = append(t.tags, tag)
+			}
+		}
+		for _, tag := range rule.Remove {
+			kept := []string{}
+			for _, existing := range t.tags {
+				if !strings.EqualFold(existing, tag) {
+					kept = append(kept, existing)
+				}
+			}
+			t.tags = kept
+		}
+		if rule.Shared != nil {
+			shared := *rule.Shared
+			t.shared = &shared
+		}
+		if rule.ToRead != nil {
+			toread := *rule.ToRead
+			t.toread = &toread
+		}
+
+		applied = append(applied, rule.label(n))
+	}
+	return applied
+}
+
+// matches reports whether every condition of m holds for t
+func (m Match) matches(t *target) bool {
+	if m.Host != "" {
+		u, err := url.Parse(t.href)
+		if err != nil {
+			return false
+		}
+		host := strings.ToLower(u.Hostname())
+		pattern := strings.ToLower(m.Host)
+		matched, _ := path.Match(pattern, host)
+		if !matched && !(strings.HasPrefix(pattern, "*.") && host == pattern[2:]) {
+			return false
+		}
+	}
+	if m.URL != "" && !containsFold(t.href, m.URL) {
+		return false
+	}
+	if m.Title != "" && !containsFold(t.title, m.Title) {
+		return false
+	}
+	if m.Description != "" && !containsFold(t.description, m.Description) {
+		return false
+	}
+	if m.Tag != "" && !hasTag(t.tags, m.Tag) {
+		return false
+	}
+	return true
+}
+
+// containsFold reports whether s contains substr, ignoring case
+func containsFold(s string, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// hasTag reports whether tags has tag, ignoring case
+func hasTag(tags []string, tag string) bool {
+	for _, existing := range tags {
+		if strings.EqualFold(existing, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// Change is a bookmark changed by the rules
+type Change struct {
+	// Before is the bookmark in the account
+	Before thumbtack.Bookmark `json:"before"`
+
+	// After is the bookmark with the rules applied
+	After thumbtack.Bookmark `json:"after"`
+
+	// Rules are the names of the rules applied
+	Rules []string `json:"rules"`
+}
+
+// Diff describes the change, one line per tag added (+) or removed (-) and per flag changed
+func (c Change) Diff() string {
+	lines := []string{c.Before.Href}
+	for _, tag := range c.After.Tags {
+		if !hasTag(c.Before.Tags, tag) {
+			lines = append(lines, "  + "+tag)
+		}
+	}
+	for _, tag := range c.Before.Tags {
+		if !hasTag(c.After.Tags, tag) {
+			lines = append(lines, "  - "+tag)
+		}
+	}
+	if c.Before.Shared != c.After.Shared {
+		lines = append(lines, fmt.Sprintf("  shared: %s -> %s", yesNo(c.Before.Shared), yesNo(c.After.Shared)))
+	}
+	if c.Before.ToRead != c.After.ToRead {
+		lines = append(lines, fmt.Sprintf("  toread: %s -> %s", yesNo(c.Before.ToRead), yesNo(c.After.ToRead)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Changes applies the rules to bookmarks and returns the bookmarks that would change, in order.
+// Nothing is changed in the account; see Backfill.
+func (s *RuleSet) Changes(bookmarks []thumbtack.Bookmark) []Change {
+	changes := []Change{}
+	for _, bookmark := range bookmarks {
+		after, applied := s.ApplyBookmark(bookmark)
+		if changed(bookmark, after) {
+			changes = append(changes, Change{Before: bookmark, After: after, Rules: applied})
+		}
+	}
+	return changes
+}
+
+// BackfillOption configures Backfill
+type BackfillOption func(b *backfiller)
+
+// backfiller holds the Backfill options
+type backfiller struct {
+	// progress. called after each change
+	progress func(done int, total int)
+}
+
+// WithProgress sets a function called after each change with the number of changes done and the total
+func WithProgress(progress func(done int, total int)) BackfillOption {
+	return func(b *backfiller) {
+		b.progress = progress
+	}
+}
+
+// BackfillResult counts the bookmarks updated by Backfill
+type BackfillResult struct {
+	// Updated is the number of bookmarks updated
+	Updated int `json:"updated"`
+
+	// Skipped is the number of bookmarks left unchanged because they have no title
+	Skipped int `json:"skipped"`
+}
+
+// Backfill saves changes to the account with PostsAdd, replacing each bookmark with its changed version
+// and keeping its time and description. Bookmarks without a title are skipped, since PostsAdd requires one.
+// A client with a rate limiter spaces the calls.
+// It stops at the first error, returning the result so far along with it.
+func Backfill(ctx context.Context, client thumbtack.PostsAPI, changes []Change, opts ...BackfillOption) (*BackfillResult, error) {
+	b := &backfiller{}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	result := &BackfillResult{}
+	for n, change := range changes {
+		if change.After.Description == "" {
+			result.Skipped++
+		} else {
+			if _, err := client.PostsAddWithContext(ctx, change.After.PostsAddInput(true)); err != nil {
+				return result, err
+			}
+			result.Updated++
+		}
+
+		if b.progress != nil {
+			b.progress(n+1, len(changes))
+		}
+	}
+	return result, nil
+}
+
+// changed reports whether the rules changed the tags or flags of a bookmark
+func changed(before thumbtack.Bookmark, after thumbtack.Bookmark) bool {
+	if before.Shared != after.Shared || before.ToRead != after.ToRead || len(before.Tags) != len(after.Tags) {
+		return true
+	}
+	for i := range before.Tags {
+		if before.Tags[i] != after.Tags[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// yesNo formats a flag like Pinboard does
+func yesNo(flag bool) string {
+	if flag {
+		return "yes"
+	}
+	return "no"
+}
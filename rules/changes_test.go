@@ -0,0 +1,93 @@
+package rules
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/thumbtacktest"
+)
+
+// TestBackfill tests previewing and applying rules to the bookmarks of an account
+func TestBackfill(t *testing.T) {
+	srv := thumbtacktest.NewServer("test:abc123")
+	defer srv.Close()
+	day := time.Date(2023, 3, 20, 16, 30, 35, 0, time.UTC)
+	srv.AddBookmark(thumbtack.Bookmark{Href: "https://docs.github.com/rest", Description: "REST API", Extended: "Docs", Tags: []string{"api"}, Time: day, Shared: true})
+	srv.AddBookmark(thumbtack.Bookmark{Href: "https://example.com", Description: "Example", Tags: []string{"misc"}, Time: day.Add(time.Hour), Shared: true})
+	srv.AddBookmark(thumbtack.Bookmark{Href: "https://github.com/x", Description: "Already tagged", Tags: []string{"github"}, Time: day.Add(2 * time.Hour)})
+
+	client := srv.QuietClient(t)
+
+	set, err := Read(strings.NewReader(yamlRules+"  - match: {host: docs.github.com}\n    shared: false\n"), FormatYAML)
+	if err != nil {
+		t.Fatalf("failed to read rules: %v", err)
+	}
+
+	bookmarks, err := client.PostsAll(&thumbtack.PostsAllInput{})
+	if err != nil {
+		t.Fatalf("failed to get bookmarks: %v", err)
+	}
+	changes := set.Changes(*bookmarks)
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 change, got %+v", changes)
+	}
+	if diff := changes[0].Diff(); diff != "https://docs.github.com/rest\n  + github\n  shared: yes -> no" {
+		t.Errorf("unexpected diff %q", diff)
+	}
+
+	progress := 0
+	result, err := Backfill(context.Background(), client, changes, WithProgress(func(done int, total int) {
+		progress = done
+	}))
+	if err != nil {
+		t.Fatalf("failed to backfill: %v", err)
+	}
+	if result.Updated != 1 || progress != 1 {
+		t.Errorf("unexpected result %+v after %d changes", result, progress)
+	}
+
+	for _, bookmark := range srv.Bookmarks() {
+		if bookmark.Href != "https://docs.github.com/rest" {
+			continue
+		}
+		if bookmark.Shared || len(bookmark.Tags) != 2 || bookmark.Tags[1] != "github" || !bookmark.Time.Equal(day) || bookmark.Extended != "Docs" {
+			t.Errorf("unexpected updated bookmark %+v", bookmark)
+		}
+	}
+
+	bookmarks, _ = client.PostsAll(&thumbtack.PostsAllInput{})
+	if changes := set.Changes(*bookmarks); len(changes) != 0 {
+		t.Errorf("expected no changes after backfilling, got %+v", changes)
+	}
+}
+
+// TestBackfillUntitled tests that a bookmark without a title is skipped rather than titled with its url
+func TestBackfillUntitled(t *testing.T) {
+	srv := thumbtacktest.NewServer("test:abc123")
+	defer srv.Close()
+	srv.AddBookmark(thumbtack.Bookmark{Href: "https://docs.github.com/untitled", Shared: true})
+	client := srv.QuietClient(t)
+
+	set, err := Read(strings.NewReader(yamlRules), FormatYAML)
+	if err != nil {
+		t.Fatalf("failed to read rules: %v", err)
+	}
+	changes := set.Changes(srv.Bookmarks())
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 change, got %+v", changes)
+	}
+
+	result, err := Backfill(context.Background(), client, changes)
+	if err != nil {
+		t.Fatalf("failed to backfill: %v", err)
+	}
+	if *result != (BackfillResult{Skipped: 1}) {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if bookmark := srv.Bookmarks()[0]; bookmark.Description != "" || len(bookmark.Tags) != 0 {
+		t.Errorf("expected the untitled bookmark to be left unchanged, got %+v", bookmark)
+	}
+}
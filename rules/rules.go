@@ -0,0 +1,201 @@
+// Package rules tags bookmarks automatically with declarative rules.
+//
+// A rule matches a bookmark by its host, url, title, description or tags, and then adds or removes tags
+// and sets the shared and toread flags. Rules are read from YAML or JSON:
+//
+//	rules:
+//	  - name: github
+//	    match:
+//	      host: "*.github.com"
+//	    add: [github]
+//	  - name: rfcs
+//	    match:
+//	      title: RFC
+//	    add: [rfc]
+//	    shared: false
+//
+// RuleSet.Apply runs the rules on a PostsAddInput before it is passed to PostsAdd. RuleSet.Changes and
+// Backfill run them on bookmarks already in the account:
+//
+//	set, err := rules.Load("rules.yaml")
+//	set.Apply(input)
+//	result, err := client.PostsAdd(input)
+//
+//	changes := set.Changes(*bookmarks) // review changes[i].Diff()
+//	result, err := rules.Backfill(ctx, client, changes)
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the format of a rules file
+type Format string
+
+const (
+	// FormatJSON reads rules as JSON
+	FormatJSON Format = "json"
+
+	// FormatYAML reads rules as YAML
+	FormatYAML Format = "yaml"
+)
+
+// ErrInvalidRule is returned when a rule cannot be read or is not valid
+type ErrInvalidRule struct {
+	Err  error
+	Msg  string
+	Rule string
+}
+
+// Error returns the error message
+func (e *ErrInvalidRule) Error() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = "invalid rule"
+	}
+	if e.Rule != "" {
+		msg += fmt.Sprintf(" %q", e.Rule)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *ErrInvalidRule) Unwrap() error {
+	return e.Err
+}
+
+// Match are the conditions of a rule, all of which must hold. Text is compared case insensitively.
+type Match struct {
+	// Host is a glob pattern for the url host, e.g. *.github.com (which also matches github.com)
+	Host string `json:"host,omitempty" yaml:"host,omitempty"`
+
+	// URL is text the url contains
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// Title is text the title contains
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+
+	// Description is text the description (Pinboard's extended) contains
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Tag is a tag the bookmark has, including tags added by earlier rules
+	Tag string `json:"tag,omitempty" yaml:"tag,omitempty"`
+}
+
+// Rule is a set of conditions and the changes made when they hold
+type Rule struct {
+	// Name identifies the rule in errors and changes
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Match are the conditions of the rule
+	Match Match `json:"match" yaml:"match"`
+
+	// Add are tags added to the bookmark
+	Add []string `json:"add,omitempty" yaml:"add,omitempty"`
+
+	// Remove are tags removed from the bookmark
+	Remove []string `json:"remove,omitempty" yaml:"remove,omitempty"`
+
+	// Shared sets whether the bookmark is public
+	Shared *bool `json:"shared,omitempty" yaml:"shared,omitempty"`
+
+	// ToRead sets whether the bookmark is marked to read
+	ToRead *bool `json:"toread,omitempty" yaml:"toread,omitempty"`
+}
+
+// RuleSet is an ordered list of rules. Every rule whose conditions hold is applied, in order.
+type RuleSet struct {
+	// Rules are the rules
+	Rules []Rule `json:"rules" yaml:"rules"`
+}
+
+// Load reads the rules file filename, as JSON if it ends in .json and as YAML otherwise
+func Load(filename string) (*RuleSet, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	format := FormatYAML
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		format = FormatJSON
+	}
+	return Read(file, format)
+}
+
+// Read reads and validates rules from r. Unknown fields are an error, to catch misspelled conditions.
+func Read(r io.Reader, format Format) (*RuleSet, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &RuleSet{}
+	switch format {
+	case FormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(set)
+	case FormatYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(set); err == io.EOF {
+			// an empty file has no rules
+			err = nil
+		}
+	default:
+		return nil, &ErrInvalidRule{Msg: fmt.Sprintf("unknown rules format %q", format)}
+	}
+	if err != nil {
+		return nil, &ErrInvalidRule{Msg: "failed to read rules", Err: err}
+	}
+
+	for n, rule := range set.Rules {
+		if err := rule.validate(); err != nil {
+			err.Rule = rule.label(n)
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// validate checks that the rule has conditions, changes and valid tags
+func (rule Rule) validate() *ErrInvalidRule {
+	if rule.Match == (Match{}) {
+		return &ErrInvalidRule{Msg: "no conditions in rule"}
+	}
+	if len(rule.Add) == 0 && len(rule.Remove) == 0 && rule.Shared == nil && rule.ToRead == nil {
+		return &ErrInvalidRule{Msg: "no changes in rule"}
+	}
+	if _, err := path.Match(rule.Match.Host, ""); err != nil {
+		return &ErrInvalidRule{Msg: "bad host pattern in rule", Err: err}
+	}
+	for _, tag := range append(append([]string{}, rule.Add...), rule.Remove...) {
+		if !thumbtack.Tag(tag).IsValid() {
+			return &ErrInvalidRule{Msg: fmt.Sprintf("tag %q must not be empty or contain whitespace or commas in rule", tag)}
+		}
+	}
+	return nil
+}
+
+// label returns the name of the n-th (from 0) rule, or #n+1 if it has none
+func (rule Rule) label(n int) string {
+	if rule.Name != "" {
+		return rule.Name
+	}
+	return fmt.Sprintf("#%d", n+1)
+}
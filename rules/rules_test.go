@@ -0,0 +1,117 @@
+package rules
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+const yamlRules = `
+rules:
+  - name: github
+    match:
+      host: "*.github.com"
+    add: [github]
+  - name: rfcs
+    match:
+      title: RFC
+    add: [rfc]
+    shared: false
+  - match:
+      tag: github
+      url: /issues/
+    add: [issue]
+    remove: [toread]
+    toread: true
+`
+
+const jsonRules = `{"rules": [
+	{"name": "github", "match": {"host": "*.github.com"}, "add": ["github"]},
+	{"name": "rfcs", "match": {"title": "RFC"}, "add": ["rfc"], "shared": false},
+	{"match": {"tag": "github", "url": "/issues/"}, "add": ["issue"], "remove": ["toread"], "toread": true}
+]}`
+
+// TestLoad tests reading the same rules from YAML and JSON
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	var sets []*RuleSet
+	for name, content := range map[string]string{"rules.yaml": yamlRules, "rules.json": jsonRules} {
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		set, err := Load(filename)
+		if err != nil {
+			t.Fatalf("failed to load %s: %v", name, err)
+		}
+		sets = append(sets, set)
+	}
+
+	if len(sets[0].Rules) != 3 || !reflect.DeepEqual(sets[0], sets[1]) {
+		t.Errorf("expected the same 3 rules, got %+v and %+v", sets[0], sets[1])
+	}
+	if rule := sets[0].Rules[1]; rule.Shared == nil || *rule.Shared || rule.ToRead != nil {
+		t.Errorf("expected rfcs to set shared only, got %+v", rule)
+	}
+}
+
+// TestReadErrors tests that invalid rules are rejected with the rule at fault
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		rule  string
+	}{
+		{`{"rules": [{"match": {"hots": "x"}, "add": ["a"]}]}`, ""},
+		{`{"rules": [{"name": "empty", "add": ["a"]}]}`, "empty"},
+		{`{"rules": [{"match": {"title": "x"}, "add": ["a"]}, {"match": {"title": "x"}}]}`, "#2"},
+		{`{"rules": [{"match": {"host": "[x"}, "add": ["a"]}]}`, "#1"},
+		{`{"rules": [{"match": {"title": "x"}, "add": ["a b"]}]}`, "#1"},
+	}
+	for _, test := range tests {
+		invalid := &ErrInvalidRule{}
+		if _, err := Read(strings.NewReader(test.input), FormatJSON); !errors.As(err, &invalid) || invalid.Rule != test.rule {
+			t.Errorf("%s: expected ErrInvalidRule for %q, got %v", test.input, test.rule, err)
+		}
+	}
+
+	if set, err := Read(strings.NewReader(""), FormatYAML); err != nil || len(set.Rules) != 0 {
+		t.Errorf("expected an empty file to have no rules, got %+v, %v", set, err)
+	}
+}
+
+// TestApply tests running rules on the input of PostsAdd
+func TestApply(t *testing.T) {
+	set, err := Read(strings.NewReader(yamlRules), FormatYAML)
+	if err != nil {
+		t.Fatalf("failed to read rules: %v", err)
+	}
+
+	href, title, shared := "https://github.com/rmrfslashbin/thumbtack/issues/1", "RFC 3339 times", true
+	tags := []string{"go", "ToRead"}
+	input := &thumbtack.PostsAddInput{Url: &href, Title: &title, Shared: &shared, Tags: tags}
+
+	applied := set.Apply(input)
+	if !reflect.DeepEqual(applied, []string{"github", "rfcs", "#3"}) {
+		t.Errorf("expected all rules to apply, got %v", applied)
+	}
+	if !reflect.DeepEqual(input.Tags, []string{"go", "github", "rfc", "issue"}) {
+		t.Errorf("unexpected tags %v", input.Tags)
+	}
+	if *input.Shared || !shared || input.ToRead == nil || !*input.ToRead {
+		t.Errorf("expected private and toread without changing the caller's flag, got %v %v", *input.Shared, input.ToRead)
+	}
+	if tags[1] != "ToRead" {
+		t.Errorf("expected the caller's tags to be left alone, got %v", tags)
+	}
+
+	other, otherTitle := "https://gitlab.com/x", "Other"
+	input = &thumbtack.PostsAddInput{Url: &other, Title: &otherTitle}
+	if applied := set.Apply(input); len(applied) != 0 || len(input.Tags) != 0 || input.Shared != nil {
+		t.Errorf("expected no rules to apply, got %v %+v", applied, input)
+	}
+}
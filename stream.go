@@ -0,0 +1,212 @@
+package thumbtack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// BookmarkIterator streams bookmarks from a posts/all response one at a time.
+// The JSON array is decoded token by token, so memory use does not grow with the size of the account.
+//
+//	it, err := client.PostsAllStream(ctx, nil)
+//	if err != nil { ... }
+//	defer it.Close()
+//	for it.Next() {
+//		bookmark := it.Bookmark()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type BookmarkIterator struct {
+	// body. the response body being decoded
+	body io.ReadCloser
+
+	// bookmark. the current bookmark
+	bookmark Bookmark
+
+	// client. used to redact and log errors; nil for iterators made with NewBookmarkIterator
+	client *Client
+
+	// ctx. the context of the call, used to report cancellation
+	ctx context.Context
+
+	// decoder. the json decoder reading body
+	decoder *json.Decoder
+
+	// done. true once the end of the array was reached or an error occurred
+	done bool
+
+	// err. the error that stopped the iteration, if any
+	err error
+
+	// started. true once the opening bracket of the array was read
+	started bool
+}
+
+// NewBookmarkIterator returns an iterator over a posts/all style JSON array of bookmarks read from body,
+// e.g. for fakes implementing PostsAPI. The iterator closes body when it is done.
+func NewBookmarkIterator(body io.ReadCloser) *BookmarkIterator {
+	return &BookmarkIterator{
+		body:    body,
+		ctx:     context.Background(),
+		decoder: json.NewDecoder(body),
+	}
+}
+
+// Next advances to the next bookmark. It returns false at the end of the response or on error;
+// check Err to tell them apart. The body is closed when Next returns false.
+func (it *BookmarkIterator) Next() bool {
+	if it.done {
+		return false
+	}
+
+	if !it.started {
+		it.started = true
+		if err := it.expectDelim('['); err != nil {
+			return it.fail(err)
+		}
+	}
+
+	if !it.decoder.More() {
+		if err := it.expectDelim(']'); err != nil {
+			return it.fail(err)
+		}
+		it.done = true
+		it.Close()
+		return false
+	}
+
+	bookmark := Bookmark{}
+	if err := it.decoder.Decode(&bookmark); err != nil {
+		return it.fail(err)
+	}
+	it.bookmark = bookmark
+	return true
+}
+
+// Bookmark returns the current bookmark
+func (it *BookmarkIterator) Bookmark() Bookmark {
+	return it.bookmark
+}
+
+// Err returns the error that stopped the iteration, or nil if the response was read completely
+func (it *BookmarkIterator) Err() error {
+	return it.err
+}
+
+// Close closes the response body. It is safe to call Close more than once.
+func (it *BookmarkIterator) Close() error {
+	it.done = true
+	if it.body == nil {
+		return nil
+	}
+	err := it.body.Close()
+	it.body = nil
+	return err
+}
+
+// expectDelim reads the next token and checks it is delim
+func (it *BookmarkIterator) expectDelim(delim json.Delim) error {
+	token, err := it.decoder.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := token.(json.Delim); !ok || d != delim {
+		return &ErrUnmarshalResponse{Api: "PostsAll", Msg: "expected " + delim.String() + " in response"}
+	}
+	return nil
+}
+
+// fail stops the iteration with err. Malformed JSON is reported as ErrUnmarshalResponse;
+// errors reading the body are returned redacted, or as ErrRequestCanceled once ctx is done.
+func (it *BookmarkIterator) fail(err error) bool {
+	var unmarshal *ErrUnmarshalResponse
+	var syntax *json.SyntaxError
+	var unmarshalType *json.UnmarshalTypeError
+	switch {
+	case it.ctx.Err() != nil:
+		err = &ErrRequestCanceled{Api: "PostsAll", Err: it.ctx.Err()}
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		err = &ErrUnmarshalResponse{Api: "PostsAll", Err: io.ErrUnexpectedEOF}
+	case errors.As(err, &unmarshal):
+		if unmarshal.Api == "" {
+			unmarshal.Api = "PostsAll"
+		}
+	case errors.As(err, &syntax) || errors.As(err, &unmarshalType):
+		err = &ErrUnmarshalResponse{Api: "PostsAll", Err: err}
+	default:
+		// the body could not be read, e.g. the connection was reset or timed out
+		if it.client != nil {
+			err = it.client.redactError(err)
+		}
+	}
+	if it.client != nil {
+		it.client.log.Error().
+			Err(err).
+			Msg("error streaming response")
+	}
+	it.err = err
+	it.Close()
+	return false
+}
+
+// PostsAllStream returns an iterator over all bookmarks in the user's account.
+// It takes the same input as PostsAll, but decodes the response one bookmark at a time.
+// The iterator must be closed if it is not read to the end.
+// https://pinboard.in/api/#posts_all
+func (c *Client) PostsAllStream(ctx context.Context, input *PostsAllInput) (*BookmarkIterator, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	v, err := c.postsAllQuery(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call the endpoint
+	postsAll, err := c.configs.GetAPI("PostsAll")
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.openEndpoint(ctx, "PostsAll", postsAll, v.Encode())
+	if err != nil {
+		c.log.Error().
+			Str("function", "thumbtack::PostsAllStream").
+			Str("endpoint", c.endpoint.String()).
+			Str("path", postsAll).
+			Str("query", c.redact(v.Encode())).
+			Msg("error calling endpoint")
+		return nil, err
+	}
+
+	return &BookmarkIterator{
+		body:    body,
+		client:  c,
+		ctx:     ctx,
+		decoder: json.NewDecoder(body),
+	}, nil
+}
+
+// PostsAllEach calls fn for every bookmark in the user's account, streaming the response.
+// Iteration stops at the first error returned by fn, which is returned as is.
+// https://pinboard.in/api/#posts_all
+func (c *Client) PostsAllEach(input *PostsAllInput, fn func(Bookmark) error) error {
+	return c.PostsAllEachWithContext(context.Background(), input, fn)
+}
+
+// PostsAllEachWithContext is the same as PostsAllEach, but uses ctx for cancellation and deadlines.
+func (c *Client) PostsAllEachWithContext(ctx context.Context, input *PostsAllInput, fn func(Bookmark) error) error {
+	it, err := c.PostsAllStream(ctx, input)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	for it.Next() {
+		if err := fn(it.Bookmark()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
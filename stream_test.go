@@ -0,0 +1,280 @@
+package thumbtack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// newStreamTestClient returns a client for a server replying body to posts/all
+func newStreamTestClient(t *testing.T, handler http.HandlerFunc) (*Client, func()) {
+	token := "test:abc123"
+	useragent := "test/1.0"
+	ts := httptest.NewServer(handler)
+
+	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(zerolog.PanicLevel)
+	url, _ := url.Parse(ts.URL)
+
+	client, err := New(
+		WithEndpoint(url),
+		WithToken(&token),
+		WithLogger(&log),
+		WithUserAgent(&useragent),
+	)
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+	return client, ts.Close
+}
+
+// TestPostsAllStream tests streaming all bookmarks
+func TestPostsAllStream(t *testing.T) {
+	count := 1000
+	client, done := newStreamTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/posts/all" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, "[")
+		for i := 0; i < count; i++ {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"href":"https:\/\/example.com\/%d","description":"example %d","extended":"","meta":"m","hash":"h","time":"2023-03-20T16:30:35Z","shared":"no","toread":"yes","tags":"test example"}`, i, i)
+		}
+		fmt.Fprint(w, "]")
+	})
+	defer done()
+
+	it, err := client.PostsAllStream(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer it.Close()
+
+	n := 0
+	for it.Next() {
+		bookmark := it.Bookmark()
+		if expected := fmt.Sprintf("https://example.com/%d", n); bookmark.Href != expected {
+			t.Fatalf("expected href '%s', got '%s'", expected, bookmark.Href)
+		}
+		if !bookmark.ToRead || bookmark.Shared {
+			t.Fatalf("expected toread bookmark that is not shared, got %+v", bookmark)
+		}
+		n++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != count {
+		t.Errorf("expected %d bookmarks, got %d", count, n)
+	}
+	if it.Next() {
+		t.Errorf("expected Next to return false after the end")
+	}
+}
+
+// TestPostsAllStreamEmpty tests streaming an empty account
+func TestPostsAllStreamEmpty(t *testing.T) {
+	client, done := newStreamTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "[]")
+	})
+	defer done()
+
+	calls := 0
+	err := client.PostsAllEach(nil, func(Bookmark) error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no bookmarks, got %d", calls)
+	}
+}
+
+// TestPostsAllStreamBadResponse tests streaming malformed responses
+func TestPostsAllStreamBadResponse(t *testing.T) {
+	for _, body := range []string{
+		"garbage",
+		`{"result_code":"something went wrong"}`,
+		`[{"href":"https:\/\/example.com","time":"2023-03-20T16:30:35Z"},`,
+		`[{"href":"https:\/\/example.com","time":"2023-03-20T16:30:35Z"} garbage]`,
+	} {
+		client, done := newStreamTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body)
+		})
+
+		err := client.PostsAllEach(nil, func(Bookmark) error { return nil })
+		var unmarshal *ErrUnmarshalResponse
+		if !errors.As(err, &unmarshal) {
+			t.Errorf("expected ErrUnmarshalResponse for %q, got %v", body, err)
+		}
+		done()
+	}
+}
+
+// failingBody is a response body that fails with err once data is read
+type failingBody struct {
+	data io.Reader
+	err  error
+}
+
+func (b *failingBody) Read(p []byte) (int, error) {
+	n, err := b.data.Read(p)
+	if err == io.EOF {
+		return n, b.err
+	}
+	return n, err
+}
+
+func (b *failingBody) Close() error {
+	return nil
+}
+
+// TestPostsAllStreamReadError tests that errors reading the body are not reported as unmarshal errors
+func TestPostsAllStreamReadError(t *testing.T) {
+	token := "test:abc123"
+	readErr := errors.New("connection reset while reading abc123")
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "200 OK",
+			Header:     http.Header{},
+			Body: &failingBody{
+				data: strings.NewReader(`[{"href":"https:\/\/example.com\/1","time":"2023-03-20T16:30:35Z"},`),
+				err:  readErr,
+			},
+			Request: r,
+		}, nil
+	})
+
+	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(zerolog.PanicLevel)
+	client, err := New(WithToken(&token), WithLogger(&log), WithTransport(transport))
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+
+	calls := 0
+	err = client.PostsAllEach(nil, func(Bookmark) error {
+		calls++
+		return nil
+	})
+	var unmarshal *ErrUnmarshalResponse
+	if errors.As(err, &unmarshal) {
+		t.Fatalf("expected a read error, not ErrUnmarshalResponse: %v", err)
+	}
+	if !errors.Is(err, readErr) {
+		t.Fatalf("expected the read error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "abc123") {
+		t.Errorf("expected the token to be redacted, got %q", err.Error())
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 bookmark before the error, got %d", calls)
+	}
+}
+
+// TestNewBookmarkIterator tests iterating over bookmarks without a client, as a fake would
+func TestNewBookmarkIterator(t *testing.T) {
+	body := io.NopCloser(strings.NewReader(`[{"href":"https:\/\/example.com\/1","time":"2023-03-20T16:30:35Z"},{"href":"https:\/\/example.com\/2","shared":true,"tags":["go"]}]`))
+	it := NewBookmarkIterator(body)
+	defer it.Close()
+
+	hrefs := []string{}
+	for it.Next() {
+		hrefs = append(hrefs, it.Bookmark().Href)
+	}
+	if it.Err() != nil {
+		t.Fatalf("unexpected error: %v", it.Err())
+	}
+	if len(hrefs) != 2 || hrefs[1] != "https://example.com/2" {
+		t.Errorf("expected 2 bookmarks, got %v", hrefs)
+	}
+
+	it = NewBookmarkIterator(io.NopCloser(strings.NewReader("garbage")))
+	if it.Next() {
+		t.Fatalf("expected no bookmarks")
+	}
+	var unmarshal *ErrUnmarshalResponse
+	if !errors.As(it.Err(), &unmarshal) {
+		t.Errorf("expected ErrUnmarshalResponse, got %v", it.Err())
+	}
+}
+
+// TestPostsAllStreamBadStatus tests that status codes are reported before streaming
+func TestPostsAllStreamBadStatus(t *testing.T) {
+	client, done := newStreamTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+	})
+	defer done()
+
+	_, err := client.PostsAllStream(context.Background(), nil)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+}
+
+// TestPostsAllStreamCallbackError tests that an error from the callback stops the iteration
+func TestPostsAllStreamCallbackError(t *testing.T) {
+	client, done := newStreamTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"href":"https:\/\/example.com\/1","time":"2023-03-20T16:30:35Z"},{"href":"https:\/\/example.com\/2","time":"2023-03-20T16:30:35Z"}]`)
+	})
+	defer done()
+
+	stop := errors.New("stop")
+	calls := 0
+	err := client.PostsAllEach(nil, func(Bookmark) error {
+		calls++
+		return stop
+	})
+	if err != stop {
+		t.Fatalf("expected the callback error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+// TestPostsAllStreamCanceled tests canceling the context while streaming
+func TestPostsAllStreamCanceled(t *testing.T) {
+	client, done := newStreamTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"href":"https:\/\/example.com\/1","time":"2023-03-20T16:30:35Z"},`)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	defer done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := client.PostsAllEachWithContext(ctx, nil, func(Bookmark) error {
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+// TestPostsAllStreamInputTagsgt3 tests that the input is validated like PostsAll
+func TestPostsAllStreamInputTagsgt3(t *testing.T) {
+	client, done := newStreamTestClient(t, func(w http.ResponseWriter, r *http.Request) {})
+	defer done()
+
+	_, err := client.PostsAllStream(context.Background(), &PostsAllInput{Tags: []string{"a", "b", "c", "d"}})
+	if _, ok := err.(*ErrInvalidInput); !ok {
+		t.Fatalf("expected ErrInvalidInput, got %T", err)
+	}
+}
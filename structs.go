@@ -2,7 +2,8 @@ package thumbtack
 
 import (
 	"encoding/json"
-	"strings"
+	"fmt"
+	"strconv"
 	"time"
 )
 
@@ -50,41 +51,38 @@ type Bookmark struct {
 	Tags []string `json:"tags"`
 }
 
-// UnmarshalJSON is a custom unmarshaler for the Bookmark struct
+// UnmarshalJSON is a custom unmarshaler for the Bookmark struct.
+// It accepts both the Pinboard wire format ("yes"/"no" flags, space separated tags)
+// and the format produced by json.Marshal (booleans, a list of tags).
 func (bookmark *Bookmark) UnmarshalJSON(b []byte) error {
-	var data map[string]interface{}
-	json.Unmarshal(b, &data)
+	data, err := decodeObject(b)
+	if err != nil {
+		return err
+	}
 
 	for key, value := range data {
 		switch key {
 		case "href":
-			bookmark.Href = value.(string)
+			err = decodeString(value, &bookmark.Href)
 		case "description":
-			bookmark.Description = value.(string)
+			err = decodeString(value, &bookmark.Description)
 		case "extended":
-			bookmark.Extended = value.(string)
+			err = decodeString(value, &bookmark.Extended)
 		case "meta":
-			bookmark.Meta = value.(string)
+			err = decodeString(value, &bookmark.Meta)
 		case "hash":
-			bookmark.Hash = value.(string)
+			err = decodeString(value, &bookmark.Hash)
 		case "time":
-			timestamp, err := time.Parse(time.RFC3339, value.(string))
-			if err != nil {
-				return err
-			}
-			bookmark.Time = timestamp
+			err = decodeTime(value, &bookmark.Time, time.RFC3339)
 		case "shared":
-			bookmark.Shared = false
-			if value == "yes" {
-				bookmark.Shared = true
-			}
+			err = decodeFlag(value, &bookmark.Shared)
 		case "toread":
-			bookmark.ToRead = false
-			if value == "yes" {
-				bookmark.ToRead = true
-			}
+			err = decodeFlag(value, &bookmark.ToRead)
 		case "tags":
-			bookmark.Tags = strings.Split(value.(string), " ")
+			err = decodeTags(value, &bookmark.Tags)
+		}
+		if err != nil {
+			return &ErrUnmarshalResponse{Body: value, Err: err, Field: key}
 		}
 	}
 	return nil
@@ -124,35 +122,34 @@ type Note struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
-// UnmarshalJSON is a custom unmarshaler for the Note struct
+// UnmarshalJSON is a custom unmarshaler for the Note struct.
+// It accepts both the Pinboard wire format ("2006-01-02 15:04:05" timestamps)
+// and the format produced by json.Marshal (RFC3339 timestamps).
 func (note *Note) UnmarshalJSON(b []byte) error {
-	var data map[string]interface{}
-	json.Unmarshal(b, &data)
+	data, err := decodeObject(b)
+	if err != nil {
+		return err
+	}
 
 	for key, value := range data {
 		switch key {
 		case "id":
-			note.Id = value.(string)
+			err = decodeString(value, &note.Id)
 		case "hash":
-			note.Hash = value.(string)
+			err = decodeString(value, &note.Hash)
 		case "title":
-			note.Title = value.(string)
+			err = decodeString(value, &note.Title)
 		case "length":
-			note.Length = value.(float64)
+			err = decodeNumber(value, &note.Length)
 		case "text":
-			note.Text = value.(string)
+			err = decodeString(value, &note.Text)
 		case "created_at":
-			timestamp, err := time.Parse(time.DateTime, value.(string))
-			if err != nil {
-				return err
-			}
-			note.CreatedAt = timestamp
+			err = decodeTime(value, &note.CreatedAt, time.DateTime, time.RFC3339)
 		case "updated_at":
-			timestamp, err := time.Parse(time.DateTime, value.(string))
-			if err != nil {
-				return err
-			}
-			note.UpdatedAt = timestamp
+			err = decodeTime(value, &note.UpdatedAt, time.DateTime, time.RFC3339)
+		}
+		if err != nil {
+			return &ErrUnmarshalResponse{Body: value, Err: err, Field: key}
 		}
 	}
 	return nil
@@ -252,17 +249,22 @@ type Suggestions struct {
 
 // UnmarshalJSON is a custom unmarshaler for the Suggestions struct
 func (suggestions *Suggestions) UnmarshalJSON(b []byte) error {
-	var data []map[string][]string
-
-	json.Unmarshal(b, &data)
+	var data []map[string]json.RawMessage
+	if err := json.Unmarshal(b, &data); err != nil {
+		return &ErrUnmarshalResponse{Body: b, Err: err}
+	}
 
 	for _, value := range data {
 		for key, value := range value {
+			var err error
 			switch key {
 			case "popular":
-				suggestions.Popular = value
+				err = decodeTags(value, &suggestions.Popular)
 			case "recommended":
-				suggestions.Recommended = value
+				err = decodeTags(value, &suggestions.Recommended)
+			}
+			if err != nil {
+				return &ErrUnmarshalResponse{Body: value, Err: err, Field: key}
 			}
 		}
 	}
@@ -284,16 +286,21 @@ type Tags struct {
 	Count int            `json:"count"`
 }
 
-// UnmarshalJSON is a custom unmarshaler for the Note struct
+// UnmarshalJSON is a custom unmarshaler for the Tags struct
 func (tags *Tags) UnmarshalJSON(b []byte) error {
-	var data map[string]int
-
-	json.Unmarshal(b, &data)
+	data, err := decodeObject(b)
+	if err != nil {
+		return err
+	}
 
 	tags.Tags = make(map[string]int, len(data))
 	tags.Count = len(data)
 	for key, value := range data {
-		tags.Tags[key] = value
+		var count float64
+		if err := decodeNumber(value, &count); err != nil {
+			return &ErrUnmarshalResponse{Body: value, Err: err, Field: key}
+		}
+		tags.Tags[key] = int(count)
 	}
 	return nil
 }
@@ -305,3 +312,108 @@ type UpdateTime struct {
 	*/
 	UpdateTime time.Time `json:"update_time"`
 }
+
+// decodeObject decodes b into its raw fields
+func decodeObject(b []byte) (map[string]json.RawMessage, error) {
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, &ErrUnmarshalResponse{Body: b, Err: err}
+	}
+	return data, nil
+}
+
+// decodeString decodes a string. null leaves dst unchanged.
+func decodeString(raw json.RawMessage, dst *string) error {
+	return json.Unmarshal(raw, dst)
+}
+
+// decodeNumber decodes a number, or a string holding a number. null leaves dst unchanged.
+func decodeNumber(raw json.RawMessage, dst *float64) error {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case nil:
+	case float64:
+		*dst = v
+	case string:
+		number, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		*dst = number
+	default:
+		return fmt.Errorf("expected a number, got %s", raw)
+	}
+	return nil
+}
+
+// decodeFlag decodes a boolean, or a Pinboard "yes"/"no" string. null leaves dst unchanged.
+func decodeFlag(raw json.RawMessage, dst *bool) error {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case nil:
+	case bool:
+		*dst = v
+	case string:
+		*dst = v == "yes"
+	default:
+		return fmt.Errorf("expected a boolean or \"yes\"/\"no\", got %s", raw)
+	}
+	return nil
+}
+
+// decodeTags decodes a list of tags, or a Pinboard space separated string of tags.
+// null leaves dst unchanged.
+func decodeTags(raw json.RawMessage, dst *[]string) error {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case nil:
+	case string:
+		*dst = parseTagStrings(v)
+	case []interface{}:
+		tags := make([]string, 0, len(v))
+		for _, tag := range v {
+			s, ok := tag.(string)
+			if !ok {
+				return fmt.Errorf("expected a list of strings, got %s", raw)
+			}
+			tags = append(tags, s)
+		}
+		*dst = tags
+	default:
+		return fmt.Errorf("expected a string or a list of strings, got %s", raw)
+	}
+	return nil
+}
+
+// decodeTime decodes a timestamp in any of layouts. null leaves dst unchanged.
+func decodeTime(raw json.RawMessage, dst *time.Time, layouts ...string) error {
+	var value *string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return err
+	}
+	if value == nil {
+		return nil
+	}
+
+	var err error
+	for _, layout := range layouts {
+		var timestamp time.Time
+		if timestamp, err = time.Parse(layout, *value); err == nil {
+			*dst = timestamp
+			return nil
+		}
+	}
+	return err
+}
@@ -2,6 +2,8 @@ package thumbtack
 
 import (
 	"encoding/json"
+	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -72,3 +74,214 @@ func TestTagsStructJsonUnmarshalFail(t *testing.T) {
 		t.Error("Expected error, got nil")
 	}
 }
+
+// TestBookmarkStructRoundTrip tests that a marshalled Bookmark unmarshals to the same Bookmark
+func TestBookmarkStructRoundTrip(t *testing.T) {
+	data := []byte(`{"href":"https:\/\/example.com","description":"example post","extended":"this is the test post\/bookmark","meta":"258002234f7274ed91cd4c50ff2f65e7","hash":"c984d06aafbecf6bc55569f964148ea3","time":"2023-03-20T16:30:35Z","shared":"yes","toread":"no","tags":"test example"}`)
+	wire := Bookmark{}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		t.Fatal(err)
+	}
+	if !wire.Shared || wire.ToRead || !reflect.DeepEqual(wire.Tags, []string{"test", "example"}) {
+		t.Errorf("unexpected bookmark %+v", wire)
+	}
+
+	exported, err := json.Marshal(wire)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bookmark := Bookmark{}
+	if err := json.Unmarshal(exported, &bookmark); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(wire, bookmark) {
+		t.Errorf("expected %+v, got %+v", wire, bookmark)
+	}
+}
+
+// TestBookmarkStructNoTags tests the Bookmark struct with an empty tags string
+func TestBookmarkStructNoTags(t *testing.T) {
+	bookmark := Bookmark{}
+	if err := json.Unmarshal([]byte(`{"href":"https:\/\/example.com","extended":null,"tags":""}`), &bookmark); err != nil {
+		t.Fatal(err)
+	}
+	if len(bookmark.Tags) != 0 {
+		t.Errorf("expected no tags, got %q", bookmark.Tags)
+	}
+}
+
+// TestBookmarkStructBadTypes tests that unexpected types return errors instead of panicking
+func TestBookmarkStructBadTypes(t *testing.T) {
+	for _, data := range []string{
+		`{"href":1}`,
+		`{"time":true}`,
+		`{"shared":1}`,
+		`{"toread":[]}`,
+		`{"tags":1}`,
+		`{"tags":["ok",1]}`,
+	} {
+		bookmark := Bookmark{}
+		if err := json.Unmarshal([]byte(data), &bookmark); err == nil {
+			t.Errorf("expected error for %s, got nil", data)
+		}
+	}
+}
+
+// TestNoteStructRoundTrip tests that a marshalled Note unmarshals to the same Note
+func TestNoteStructRoundTrip(t *testing.T) {
+	notesByIdResp := `{"id":"xxxx67e342662e6c239c","title":"Test Note 01","created_at":"2023-03-19 14:35:16","updated_at":"2023-03-19 14:35:16","length":"40","text":"This is my test note to see how it works","hash":"xxxx910a03859fd9e80a"}`
+	wire := Note{}
+	if err := json.Unmarshal([]byte(notesByIdResp), &wire); err != nil {
+		t.Fatal(err)
+	}
+	if wire.Length != 40 {
+		t.Errorf("expected length 40, got %v", wire.Length)
+	}
+
+	exported, err := json.Marshal(wire)
+	if err != nil {
+		t.Fatal(err)
+	}
+	note := Note{}
+	if err := json.Unmarshal(exported, &note); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(wire, note) {
+		t.Errorf("expected %+v, got %+v", wire, note)
+	}
+}
+
+// TestNoteStructBadTypes tests that unexpected types return errors instead of panicking
+func TestNoteStructBadTypes(t *testing.T) {
+	for _, data := range []string{
+		`{"id":1}`,
+		`{"length":true}`,
+		`{"length":"forty"}`,
+		`{"created_at":1}`,
+	} {
+		note := Note{}
+		if err := json.Unmarshal([]byte(data), &note); err == nil {
+			t.Errorf("expected error for %s, got nil", data)
+		}
+	}
+}
+
+// TestStructsFieldErrors tests that decoding errors name the field at fault
+func TestStructsFieldErrors(t *testing.T) {
+	tests := []struct {
+		data  string
+		v     interface{}
+		field string
+	}{
+		{`{"href":"https://example.com","extended":1}`, &Bookmark{}, "extended"},
+		{`{"id":"1","length":[]}`, &Note{}, "length"},
+		{`{"books":1,"custom":"many"}`, &Tags{}, "custom"},
+		{`[{"popular":["go"]},{"recommended":7}]`, &Suggestions{}, "recommended"},
+	}
+	for _, test := range tests {
+		err := json.Unmarshal([]byte(test.data), test.v)
+		unmarshal := &ErrUnmarshalResponse{}
+		if !errors.As(err, &unmarshal) {
+			t.Errorf("expected ErrUnmarshalResponse for %s, got %v", test.data, err)
+			continue
+		}
+		if FieldOf(err) != test.field {
+			t.Errorf("expected field %q for %s, got %q", test.field, test.data, FieldOf(err))
+		}
+	}
+}
+
+// TestStructsNotAnObject tests that well formed json of the wrong shape returns an error
+func TestStructsNotAnObject(t *testing.T) {
+	for _, v := range []interface{}{&Bookmark{}, &Note{}, &Tags{}, &Suggestions{}} {
+		data := `[1]`
+		if _, ok := v.(*Suggestions); ok {
+			data = `{"popular":[]}`
+		}
+		err := json.Unmarshal([]byte(data), v)
+		unmarshal := &ErrUnmarshalResponse{}
+		if !errors.As(err, &unmarshal) {
+			t.Errorf("expected ErrUnmarshalResponse for %T, got %v", v, err)
+		}
+	}
+}
+
+// fuzzStruct fuzzes json.Unmarshal into the value returned by newValue. Decoding must never panic.
+// If roundTrip is true, whatever decodes must also survive a marshal round trip.
+func fuzzStruct(f *testing.F, newValue func() interface{}, roundTrip bool, seeds ...string) {
+	for _, seed := range seeds {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v := newValue()
+		if err := json.Unmarshal(data, v); err != nil || !roundTrip {
+			return
+		}
+		exported, err := json.Marshal(v)
+		if err != nil {
+			// e.g. a timestamp outside of the years json.Marshal supports
+			return
+		}
+		if err := json.Unmarshal(exported, newValue()); err != nil {
+			t.Errorf("failed to unmarshal %s after marshalling %s: %v", exported, data, err)
+		}
+	})
+}
+
+func FuzzBookmark(f *testing.F) {
+	fuzzStruct(f, func() interface{} { return &Bookmark{} }, true,
+		`{"href":"https:\/\/example.com","description":"example post","extended":"","meta":"258002234f7274ed91cd4c50ff2f65e7","hash":"c984d06aafbecf6bc55569f964148ea3","time":"2023-03-20T16:30:35Z","shared":"no","toread":"yes","tags":"test example"}`,
+		`{"href":"https://example.com","extended":null,"time":"2023-03-20T16:30:35Z","shared":true,"toread":false,"tags":["test","example"]}`,
+	)
+}
+
+func FuzzDates(f *testing.F) {
+	fuzzStruct(f, func() interface{} { return &Dates{} }, true,
+		`{"user":"rmrfslashbin","tag":"","dates":{"2023-03-19":4,"2023-03-12":1}}`,
+	)
+}
+
+func FuzzNote(f *testing.F) {
+	fuzzStruct(f, func() interface{} { return &Note{} }, true,
+		`{"id":"xxxx67e342662e6c239c","title":"Test Note 01","created_at":"2023-03-19 14:35:16","updated_at":"2023-03-19 14:35:16","length":40,"text":"This is my test note","hash":"xxxx910a03859fd9e80a"}`,
+		`{"id":"1","length":"40","created_at":"2023-03-19T14:35:16Z","updated_at":null}`,
+	)
+}
+
+func FuzzNotes(f *testing.F) {
+	fuzzStruct(f, func() interface{} { return &Notes{} }, true,
+		`{"count":1,"notes":[{"id":"1e5467e342662e6c239c","hash":"e652910a03859fd9e80a","title":"Test Note 01","length":40,"created_at":"2023-03-19 14:35:16","updated_at":"2023-03-19 14:35:16"}]}`,
+	)
+}
+
+func FuzzPosts(f *testing.F) {
+	fuzzStruct(f, func() interface{} { return &Posts{} }, true,
+		`{"date":"2023-03-10T01:32:09Z","user":"rmrfslashbin","posts":[{"href":"https://example.com","time":"2023-03-10T01:32:09Z","shared":"no","toread":"yes","tags":"books"}]}`,
+	)
+}
+
+func FuzzResult(f *testing.F) {
+	fuzzStruct(f, func() interface{} { return &Result{} }, true,
+		`{"result":"done"}`,
+		`{"result_code":"missing url"}`,
+	)
+}
+
+func FuzzSuggestions(f *testing.F) {
+	fuzzStruct(f, func() interface{} { return &Suggestions{} }, false,
+		`[{"popular":["fonts","css","design"]},{"recommended":["typography","web","via:popular"]}]`,
+	)
+}
+
+func FuzzTags(f *testing.F) {
+	fuzzStruct(f, func() interface{} { return &Tags{} }, false,
+		`{"books":1,"custom":1,"haproxy":2}`,
+		`{"books":"1"}`,
+	)
+}
+
+func FuzzUpdateTime(f *testing.F) {
+	fuzzStruct(f, func() interface{} { return &UpdateTime{} }, true,
+		`{"update_time":"2023-03-19T15:57:02Z"}`,
+	)
+}
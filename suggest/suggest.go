@@ -0,0 +1,329 @@
+// Package suggest ranks tags for a new bookmark by learning from the bookmarks already in an account.
+//
+// Pinboard's posts/suggest only knows public urls, so for private or intranet urls it usually
+// suggests nothing. A Model learns which tags go with which url hosts, path segments and words in the
+// title and description, and which tags are used together:
+//
+//	bookmarks, err := client.PostsAll(&thumbtack.PostsAllInput{})
+//	model := suggest.Train(*bookmarks)
+//	local := model.Suggest(thumbtack.Bookmark{Href: href, Description: title}, 10)
+//
+//	remote, err := client.PostsSuggest(href)
+//	suggestions := suggest.Merge(local, remote, 10)
+package suggest
+
+import (
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+const (
+	// SourceLocal marks a suggestion made by a Model
+	SourceLocal = "local"
+
+	// SourceRecommended marks a suggestion recommended by posts/suggest
+	SourceRecommended = "recommended"
+
+	// SourcePopular marks a suggestion popular according to posts/suggest
+	SourcePopular = "popular"
+)
+
+// weights of the evidence for a tag
+const (
+	// hostWeight. a token from the url host says more than a word
+	hostWeight = 2.0
+
+	// nameWeight. the tag itself appears in the url, title or description
+	nameWeight = 1.0
+
+	// cooccurrenceWeight. the tag is used with a likely tag, or a tag the bookmark already has
+	cooccurrenceWeight = 0.5
+
+	// recommendedWeight and popularWeight. the tag is suggested by posts/suggest
+	recommendedWeight = 0.5
+	popularWeight     = 0.25
+)
+
+// stopwords are tokens too common to say anything about tags
+var stopwords = map[string]bool{
+	"www": true, "com": true, "org": true, "net": true, "html": true, "htm": true, "php": true, "index": true,
+	"http": true, "https": true, "the": true, "and": true, "for": true, "with": true, "from": true, "that": true,
+	"this": true, "you": true, "your": true, "are": true, "was": true, "how": true, "what": true, "why": true,
+	"not": true, "but": true, "all": true, "can": true, "has": true, "have": true, "into": true, "about": true,
+}
+
+// Suggestion is a ranked tag
+type Suggestion struct {
+	// Tag is the suggested tag
+	Tag string `json:"tag"`
+
+	// Score ranks the suggestion; the best local suggestion scores 1
+	Score float64 `json:"score"`
+
+	// Sources are where the suggestion came from: SourceLocal, SourceRecommended or SourcePopular
+	Sources []string `json:"sources"`
+}
+
+// Model holds what was learned from the bookmarks of an account
+type Model struct {
+	// bookmarks. the number of bookmarks learned from
+	bookmarks int
+
+	// names. the spelling of every tag, by lower case tag
+	names map[string]string
+
+	// tagCount. the number of bookmarks with each tag
+	tagCount map[string]int
+
+	// tokenCount. the number of bookmarks with each token
+	tokenCount map[string]int
+
+	// tokenTags. the number of bookmarks with each token and tag
+	tokenTags map[string]map[string]int
+
+	// cooccurrence. the number of bookmarks with each pair of tags
+	cooccurrence map[string]map[string]int
+}
+
+// Train returns a model learned from bookmarks
+func Train(bookmarks []thumbtack.Bookmark) *Model {
+	m := &Model{
+		names:        map[string]string{},
+		tagCount:     map[string]int{},
+		tokenCount:   map[string]int{},
+		tokenTags:    map[string]map[string]int{},
+		cooccurrence: map[string]map[string]int{},
+	}
+	for _, bookmark := range bookmarks {
+		m.Learn(bookmark)
+	}
+	return m
+}
+
+// Learn adds bookmark to the model. Untagged bookmarks and system tags (via:) are ignored.
+func (m *Model) Learn(bookmark thumbtack.Bookmark) {
+	tags := m.tagsOf(bookmark, true)
+	if len(tags) == 0 {
+		return
+	}
+	m.bookmarks++
+
+	for _, tag := range tags {
+		m.tagCount[tag]++
+		for _, other := range tags {
+			if other != tag {
+				if m.cooccurrence[tag] == nil {
+					m.cooccurrence[tag] = map[string]int{}
+				}
+				m.cooccurrence[tag][other]++
+			}
+		}
+	}
+
+	for token := range tokenize(bookmark) {
+		m.tokenCount[token]++
+		if m.tokenTags[token] == nil {
+			m.tokenTags[token] = map[string]int{}
+		}
+		for _, tag := range tags {
+			m.tokenTags[token][tag]++
+		}
+	}
+}
+
+// Suggest returns up to limit tags for bookmark, best first, from its url, title (Description) and Extended.
+// Tags the bookmark already has are not suggested, but make the tags used with them more likely.
+func (m *Model) Suggest(bookmark thumbtack.Bookmark, limit int) []Suggestion {
+	suggestions := []Suggestion{}
+	if m.bookmarks == 0 || limit <= 0 {
+		return suggestions
+	}
+
+	have := map[string]bool{}
+	for _, tag := range m.tagsOf(bookmark, false) {
+		have[tag] = true
+	}
+
+	// evidence from tokens: how often the tag goes with the token, weighted by how rare the token is
+	scores := map[string]float64{}
+	tokens := tokenize(bookmark)
+	for token := range tokens {
+		docs := m.tokenCount[token]
+		if docs == 0 {
+			continue
+		}
+		weight := math.Log(1 + float64(m.bookmarks)/float64(docs))
+		if strings.HasPrefix(token, hostPrefix) {
+			weight *= hostWeight
+		}
+		for tag, count := range m.tokenTags[token] {
+			scores[tag] += weight * float64(count) / float64(docs)
+		}
+	}
+
+	// evidence from the tag itself appearing in the bookmark
+	for tag := range m.tagCount {
+		if tokens[tag] {
+			scores[tag] += nameWeight
+		}
+	}
+
+	// evidence from tags used together with the likely tags and the tags the bookmark has
+	boosts := map[string]float64{}
+	for tag, score := range scores {
+		m.boost(boosts, tag, score)
+	}
+	for tag := range have {
+		m.boost(boosts, tag, 1)
+	}
+	for tag, boost := range boosts {
+		scores[tag] += cooccurrenceWeight * boost
+	}
+
+	best := 0.0
+	for tag, score := range scores {
+		if have[tag] {
+			continue
+		}
+		suggestions = append(suggestions, Suggestion{Tag: m.names[tag], Score: score, Sources: []string{SourceLocal}})
+		best = math.Max(best, score)
+	}
+	for i := range suggestions {
+		suggestions[i].Score /= best
+	}
+
+	sortSuggestions(suggestions)
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// boost adds to boosts the likelihood of every tag used with tag, scaled by score
+func (m *Model) boost(boosts map[string]float64, tag string, score float64) {
+	total := m.tagCount[tag]
+	if total == 0 {
+		return
+	}
+	for other, count := range m.cooccurrence[tag] {
+		boosts[other] += score * float64(count) / float64(total)
+	}
+}
+
+// tagsOf returns the lower case tags of bookmark, leaving out system tags.
+// If learn is true, the spelling of the tags is recorded; otherwise only known tags are returned.
+func (m *Model) tagsOf(bookmark thumbtack.Bookmark, learn bool) []string {
+	tags := []string{}
+	seen := map[string]bool{}
+	for _, tag := range bookmark.Tags {
+		key := strings.ToLower(tag)
+		if tag == "" || thumbtack.Tag(tag).IsSystem() || seen[key] {
+			continue
+		}
+		if learn {
+			if _, ok := m.names[key]; !ok {
+				m.names[key] = tag
+			}
+		} else if _, ok := m.names[key]; !ok {
+			continue
+		}
+		seen[key] = true
+		tags = append(tags, key)
+	}
+	return tags
+}
+
+// Merge combines local suggestions with those of posts/suggest and returns up to limit tags, best first.
+// Tags suggested by both add up their scores; remote may be nil.
+func Merge(local []Suggestion, remote *thumbtack.Suggestions, limit int) []Suggestion {
+	merged := []Suggestion{}
+	index := map[string]int{}
+
+	add := func(tag string, score float64, source string) {
+		key := strings.ToLower(tag)
+		if tag == "" || thumbtack.Tag(tag).IsSystem() {
+			return
+		}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			merged = append(merged, Suggestion{Tag: tag, Score: score, Sources: []string{source}})
+			return
+		}
+		merged[i].Score += score
+		for _, existing := range merged[i].Sources {
+			if existing == source {
+				return
+			}
+		}
+		merged[i].Sources = append(merged[i].Sources, source)
+	}
+
+	for _, suggestion := range local {
+		add(suggestion.Tag, suggestion.Score, SourceLocal)
+	}
+	if remote != nil {
+		for _, tag := range remote.Recommended {
+			add(tag, recommendedWeight, SourceRecommended)
+		}
+		for _, tag := range remote.Popular {
+			add(tag, popularWeight, SourcePopular)
+		}
+	}
+
+	sortSuggestions(merged)
+	if limit >= 0 && len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged
+}
+
+// sortSuggestions sorts by score, best first, then by tag
+func sortSuggestions(suggestions []Suggestion) {
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Tag < suggestions[j].Tag
+	})
+}
+
+// hostPrefix marks tokens from the url host
+const hostPrefix = "host:"
+
+// tokenize returns the tokens of a bookmark: its url host and registered domain (e.g. host:docs.github.com
+// and host:github.com), and the lower case words of its url path, title and description
+func tokenize(bookmark thumbtack.Bookmark) map[string]bool {
+	tokens := map[string]bool{}
+
+	if u, err := url.Parse(bookmark.Href); err == nil && u.Hostname() != "" {
+		host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+		tokens[hostPrefix+host] = true
+		if labels := strings.Split(host, "."); len(labels) > 2 {
+			tokens[hostPrefix+strings.Join(labels[len(labels)-2:], ".")] = true
+		}
+		addWords(tokens, u.Path)
+	}
+	addWords(tokens, bookmark.Description)
+	addWords(tokens, bookmark.Extended)
+
+	return tokens
+}
+
+// addWords adds the words of text to tokens, leaving out stopwords, numbers and single letters
+func addWords(tokens map[string]bool, text string) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+	})
+	for _, word := range words {
+		if len(word) < 2 || stopwords[word] || strings.IndexFunc(word, unicode.IsLetter) < 0 {
+			continue
+		}
+		tokens[word] = true
+	}
+}
@@ -0,0 +1,74 @@
+package suggest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+var bookmarks = []thumbtack.Bookmark{
+	{Href: "https://wiki.intranet.example/ops/kubernetes/upgrade", Description: "Upgrading the cluster", Tags: []string{"ops", "Kubernetes"}},
+	{Href: "https://wiki.intranet.example/ops/postgres/backup", Description: "Database backups", Tags: []string{"ops", "postgres", "via:popular"}},
+	{Href: "https://go.dev/doc/effective_go", Description: "Effective Go", Tags: []string{"go", "programming"}},
+	{Href: "https://pkg.go.dev/net/http", Description: "http package", Tags: []string{"go", "http"}},
+	{Href: "https://example.com/untagged", Description: "Nothing"},
+}
+
+// tagsOf returns the tags of suggestions
+func tagsOf(suggestions []Suggestion) []string {
+	tags := []string{}
+	for _, suggestion := range suggestions {
+		tags = append(tags, suggestion.Tag)
+	}
+	return tags
+}
+
+// TestSuggest tests ranking tags for new bookmarks
+func TestSuggest(t *testing.T) {
+	model := Train(bookmarks)
+
+	suggestions := model.Suggest(thumbtack.Bookmark{Href: "https://wiki.intranet.example/ops/kubernetes/ingress", Description: "Ingress setup"}, 3)
+	if tags := tagsOf(suggestions); len(tags) != 3 || tags[0] != "ops" || tags[1] != "Kubernetes" {
+		t.Errorf("expected ops and Kubernetes first, got %v", suggestions)
+	}
+	if suggestions[0].Score != 1 || !reflect.DeepEqual(suggestions[0].Sources, []string{SourceLocal}) {
+		t.Errorf("expected the best suggestion to score 1 locally, got %+v", suggestions[0])
+	}
+
+	// a tag the bookmark has is not suggested, but suggests the tags used with it
+	suggestions = model.Suggest(thumbtack.Bookmark{Href: "https://internal.example/x", Description: "Notes", Tags: []string{"GO"}}, 5)
+	if tags := tagsOf(suggestions); len(tags) != 2 || tags[0] != "http" && tags[0] != "programming" {
+		t.Errorf("expected http and programming, got %v", suggestions)
+	}
+
+	for _, suggestion := range model.Suggest(thumbtack.Bookmark{Href: "https://wiki.intranet.example/ops/postgres/restore"}, 10) {
+		if suggestion.Tag == "via:popular" {
+			t.Errorf("expected no system tags, got %v", suggestion)
+		}
+	}
+
+	if suggestions := Train(nil).Suggest(thumbtack.Bookmark{Href: "https://go.dev"}, 5); len(suggestions) != 0 {
+		t.Errorf("expected no suggestions from an empty model, got %v", suggestions)
+	}
+}
+
+// TestMerge tests combining local and posts/suggest suggestions
+func TestMerge(t *testing.T) {
+	local := []Suggestion{{Tag: "go", Score: 1, Sources: []string{SourceLocal}}, {Tag: "http", Score: 0.2, Sources: []string{SourceLocal}}}
+	remote := &thumbtack.Suggestions{Popular: []string{"golang", "via:popular"}, Recommended: []string{"Go", "web"}}
+
+	merged := Merge(local, remote, 3)
+	want := []Suggestion{
+		{Tag: "go", Score: 1.5, Sources: []string{SourceLocal, SourceRecommended}},
+		{Tag: "web", Score: 0.5, Sources: []string{SourceRecommended}},
+		{Tag: "golang", Score: 0.25, Sources: []string{SourcePopular}},
+	}
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("expected %+v, got %+v", want, merged)
+	}
+
+	if merged := Merge(local, nil, 10); len(merged) != 2 {
+		t.Errorf("expected the local suggestions without remote ones, got %+v", merged)
+	}
+}
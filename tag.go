@@ -0,0 +1,119 @@
+package thumbtack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// PrivateTagPrefix marks a private tag. Private tags are only visible to the account owner.
+const PrivateTagPrefix = "."
+
+// SystemTagPrefix marks a system tag added by Pinboard, e.g. "via:popular"
+const SystemTagPrefix = "via:"
+
+// Tag is a single Pinboard tag
+type Tag string
+
+// IsPrivate reports whether the tag is a private tag (leading ".")
+func (t Tag) IsPrivate() bool {
+	return len(t) > len(PrivateTagPrefix) && strings.HasPrefix(string(t), PrivateTagPrefix)
+}
+
+// IsSystem reports whether the tag is a system tag (leading "via:")
+func (t Tag) IsSystem() bool {
+	return len(t) > len(SystemTagPrefix) && strings.HasPrefix(strings.ToLower(string(t)), SystemTagPrefix)
+}
+
+// IsValid reports whether Pinboard keeps the tag whole: it is not empty and has no whitespace or commas
+func (t Tag) IsValid() bool {
+	return t != "" && strings.IndexFunc(string(t), func(r rune) bool { return unicode.IsSpace(r) || r == ',' }) < 0
+}
+
+// String returns the tag as a string
+func (t Tag) String() string {
+	return string(t)
+}
+
+// HasTags reports whether the bookmark has all tags, compared case insensitively like Pinboard does
+func (bookmark Bookmark) HasTags(tags ...string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, t := range bookmark.Tags {
+			if strings.EqualFold(t, tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseTags parses a Pinboard space separated list of tags.
+// Runs of whitespace separate tags, so no tags (or only whitespace) is an empty, non-nil slice.
+func ParseTags(s string) []Tag {
+	fields := strings.Fields(s)
+	tags := make([]Tag, 0, len(fields))
+	for _, field := range fields {
+		tags = append(tags, Tag(field))
+	}
+	return tags
+}
+
+// parseTagStrings parses a Pinboard space separated list of tags into strings, as stored in Bookmark.Tags
+func parseTagStrings(s string) []string {
+	tags := ParseTags(s)
+	strs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		strs = append(strs, tag.String())
+	}
+	return strs
+}
+
+// joinTags validates tags and joins them into the Pinboard space separated format.
+// Empty tags are ignored. A tag containing whitespace or a comma would be split by Pinboard and is an error,
+// as is more than max tags.
+func joinTags(tags []string, max int) (string, error) {
+	valid := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag == "" {
+			continue
+		}
+		if !Tag(tag).IsValid() {
+			return "", &ErrInvalidInput{Msg: fmt.Sprintf("tag %q must not contain whitespace or commas", tag)}
+		}
+		valid = append(valid, tag)
+	}
+
+	if len(valid) > max {
+		return "", &ErrInvalidInput{Msg: fmt.Sprintf("tags must be less than or equal to %d", max)}
+	}
+
+	return strings.Join(valid, " "), nil
+}
+
+// Private returns the private tags, sorted
+func (tags *Tags) Private() []Tag {
+	return tags.filter(Tag.IsPrivate)
+}
+
+// System returns the system tags, sorted
+func (tags *Tags) System() []Tag {
+	return tags.filter(Tag.IsSystem)
+}
+
+// filter returns the tags for which keep is true, sorted
+func (tags *Tags) filter(keep func(Tag) bool) []Tag {
+	filtered := []Tag{}
+	for name := range tags.Tags {
+		if tag := Tag(name); keep(tag) {
+			filtered = append(filtered, tag)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool { return filtered[i] < filtered[j] })
+	return filtered
+}
@@ -0,0 +1,164 @@
+package thumbtack
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// TestParseTags tests parsing of space separated tags
+func TestParseTags(t *testing.T) {
+	tests := map[string][]Tag{
+		"":                   {},
+		"   ":                {},
+		"go":                 {"go"},
+		"go  test":           {"go", "test"},
+		" .private via:x\t ": {".private", "via:x"},
+	}
+	for input, expected := range tests {
+		tags := ParseTags(input)
+		if tags == nil || !reflect.DeepEqual(tags, expected) {
+			t.Errorf("ParseTags(%q) = %#v, want %#v", input, tags, expected)
+		}
+	}
+}
+
+// TestTagIsPrivateAndIsSystem tests the private and system tag helpers
+func TestTagIsPrivateAndIsSystem(t *testing.T) {
+	tests := []struct {
+		tag     Tag
+		private bool
+		system  bool
+	}{
+		{"go", false, false},
+		{".secret", true, false},
+		{".", false, false},
+		{"via:popular", false, true},
+		{"VIA:Someone", false, true},
+		{"via:", false, false},
+		{"viaduct", false, false},
+	}
+	for _, test := range tests {
+		if test.tag.IsPrivate() != test.private {
+			t.Errorf("%q.IsPrivate() = %v, want %v", test.tag, test.tag.IsPrivate(), test.private)
+		}
+		if test.tag.IsSystem() != test.system {
+			t.Errorf("%q.IsSystem() = %v, want %v", test.tag, test.tag.IsSystem(), test.system)
+		}
+	}
+}
+
+// TestTagIsValid tests that tags Pinboard would split or drop are not valid
+func TestTagIsValid(t *testing.T) {
+	for tag, valid := range map[Tag]bool{"go": true, ".secret": true, "via:x": true, "": false, "a b": false, "a,b": false, "a\tb": false} {
+		if tag.IsValid() != valid {
+			t.Errorf("%q.IsValid() = %v, want %v", tag, tag.IsValid(), valid)
+		}
+	}
+}
+
+// TestBookmarkHasTags tests matching tags case insensitively
+func TestBookmarkHasTags(t *testing.T) {
+	bookmark := Bookmark{Tags: []string{"Go", "testing"}}
+	tests := []struct {
+		tags     []string
+		expected bool
+	}{
+		{nil, true},
+		{[]string{"go"}, true},
+		{[]string{"GO", "Testing"}, true},
+		{[]string{"go", "rust"}, false},
+		{[]string{"test"}, false},
+	}
+	for _, test := range tests {
+		if got := bookmark.HasTags(test.tags...); got != test.expected {
+			t.Errorf("HasTags(%q) = %t, want %t", test.tags, got, test.expected)
+		}
+	}
+}
+
+// TestBookmarkStructUntagged tests that untagged bookmarks and double spaces do not produce empty tags
+func TestBookmarkStructUntagged(t *testing.T) {
+	for data, expected := range map[string][]string{
+		`{"tags":""}`:          {},
+		`{"tags":"go  test "}`: {"go", "test"},
+	} {
+		bookmark := Bookmark{}
+		if err := json.Unmarshal([]byte(data), &bookmark); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(bookmark.Tags, expected) {
+			t.Errorf("%s: expected %q, got %q", data, expected, bookmark.Tags)
+		}
+	}
+}
+
+// TestTagsPrivateAndSystem tests selecting private and system tags from Tags
+func TestTagsPrivateAndSystem(t *testing.T) {
+	tags := Tags{}
+	if err := json.Unmarshal([]byte(`{"go":3,".todo":1,"via:popular":2,".diary":4}`), &tags); err != nil {
+		t.Fatal(err)
+	}
+	if private := tags.Private(); !reflect.DeepEqual(private, []Tag{".diary", ".todo"}) {
+		t.Errorf("unexpected private tags %q", private)
+	}
+	if system := tags.System(); !reflect.DeepEqual(system, []Tag{"via:popular"}) {
+		t.Errorf("unexpected system tags %q", system)
+	}
+}
+
+// TestTagsQuery tests that PostsAdd and PostsAll send clean tags and reject tags Pinboard would split
+func TestTagsQuery(t *testing.T) {
+	query := url.Values{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		if r.URL.Path == "/posts/all" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`{"result_code":"done"}`))
+	}))
+	defer ts.Close()
+
+	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(zerolog.PanicLevel)
+	endpoint, _ := url.Parse(ts.URL)
+	token := "test:abc123"
+	client, err := New(WithEndpoint(endpoint), WithToken(&token), WithLogger(&log))
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+
+	href := "https://example.com"
+	title := "Example"
+	if _, err := client.PostsAdd(&PostsAddInput{Url: &href, Title: &title, Tags: []string{"go", "", ".private"}}); err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("tags") != "go .private" {
+		t.Errorf("expected tags %q, got %q", "go .private", query.Get("tags"))
+	}
+
+	if _, err := client.PostsAll(&PostsAllInput{Tags: []string{"", "go", "", "via:popular", ""}}); err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("tag") != "go via:popular" {
+		t.Errorf("expected tag %q, got %q", "go via:popular", query.Get("tag"))
+	}
+
+	invalid := &ErrInvalidInput{}
+	_, err = client.PostsAdd(&PostsAddInput{Url: &href, Title: &title, Tags: []string{"two words"}})
+	if !errors.As(err, &invalid) {
+		t.Errorf("expected ErrInvalidInput for a tag with a space, got %v", err)
+	}
+	_, err = client.PostsAll(&PostsAllInput{Tags: []string{"a,b"}})
+	if !errors.As(err, &invalid) {
+		t.Errorf("expected ErrInvalidInput for a tag with a comma, got %v", err)
+	}
+}
@@ -0,0 +1,344 @@
+// Package taglint finds inconsistent tags in an account and proposes renames to clean them up.
+//
+// Lint reports
+//   - tags that differ only in case, e.g. Golang and golang
+//   - near duplicates, e.g. go-lang and golang, or javascript and javascipt
+//   - singular and plural pairs, e.g. library and libraries
+//   - tags used only once
+//   - tags with characters Pinboard mishandles, e.g. & or "
+//
+// and proposes a mapping that renames each group of case variants and singular/plural pairs to its most
+// used tag. Near duplicates are only included when neither tag has another near duplicate. The mapping
+// is meant to be reviewed and then applied with the remap package:
+//
+//	tags, err := client.TagsGet()
+//	report := taglint.Lint(tags)
+//	err = report.Mapping.Write(file) // thumbtack tags remap --file <file>
+package taglint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/remap"
+)
+
+// Kind is the kind of an issue
+type Kind string
+
+const (
+	// KindCase is a group of tags that differ only in case
+	KindCase Kind = "case"
+
+	// KindNearDuplicate is a pair of tags within a small edit distance of each other
+	KindNearDuplicate Kind = "near-duplicate"
+
+	// KindPlural is a singular and plural pair of tags
+	KindPlural Kind = "plural"
+
+	// KindSingleUse is a tag used by only one bookmark
+	KindSingleUse Kind = "single-use"
+
+	// KindCharacters is a tag with characters Pinboard mishandles
+	KindCharacters Kind = "characters"
+)
+
+// kindOrder is the order of kinds in a report
+var kindOrder = map[Kind]int{KindCase: 0, KindNearDuplicate: 1, KindPlural: 2, KindCharacters: 3, KindSingleUse: 4}
+
+// mishandled are the characters Pinboard splits tags at, or which break its tag urls and search
+const mishandled = `,"'&+<>\?#%`
+
+// Issue is a problem found by Lint
+type Issue struct {
+	// Kind is the kind of the issue
+	Kind Kind `json:"kind"`
+
+	// Tags are the tags involved, sorted
+	Tags []string `json:"tags"`
+
+	// Message describes the issue
+	Message string `json:"message"`
+}
+
+// Report is the result of Lint
+type Report struct {
+	// Issues are the problems found, ordered by kind and tags
+	Issues []Issue `json:"issues"`
+
+	// Mapping renames each group of case variants and singular/plural pairs, and each pair of near duplicates
+	// with no other near duplicate, to its most used tag
+	Mapping remap.Mapping `json:"mapping"`
+}
+
+// Option configures Lint
+type Option func(l *linter)
+
+// linter holds the Lint options
+type linter struct {
+	// maxDistance. the largest edit distance between near duplicates
+	maxDistance int
+}
+
+// WithMaxDistance sets the largest edit distance between near duplicates. Default is 2.
+// Short tags are held to a smaller distance: 1 for tags of 4 to 6 characters, none below that.
+// Zero only reports tags equal after removing - and _.
+func WithMaxDistance(distance int) Option {
+	return func(l *linter) {
+		l.maxDistance = distance
+	}
+}
+
+// Lint checks tags, as returned by TagsGet. System tags (via:) are left out, as Pinboard adds them.
+func Lint(tags *thumbtack.Tags, opts ...Option) *Report {
+	l := &linter{maxDistance: 2}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	counts := map[string]int{}
+	names := []string{}
+	if tags != nil {
+		for name, count := range tags.Tags {
+			if thumbtack.Tag(name).IsSystem() {
+				continue
+			}
+			counts[name] = count
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	report := &Report{Issues: []Issue{}, Mapping: remap.Mapping{}}
+	groups := newUnionFind(names)
+
+	// case only duplicates
+	byLower := map[string][]string{}
+	for _, name := range names {
+		lower := strings.ToLower(name)
+		byLower[lower] = append(byLower[lower], name)
+	}
+	for _, variants := range byLower {
+		if len(variants) > 1 {
+			report.add(KindCase, variants, "tags differ only in case")
+			for _, variant := range variants[1:] {
+				groups.union(variants[0], variant)
+			}
+		}
+	}
+
+	// singular/plural pairs and near duplicates, compared once per lower case spelling
+	lowers := make([]string, 0, len(byLower))
+	for lower := range byLower {
+		lowers = append(lowers, lower)
+	}
+	sort.Strings(lowers)
+	near := [][]string{}
+	for i, a := range lowers {
+		for _, b := range lowers[i+1:] {
+			pair := []string{byLower[a][0], byLower[b][0]}
+			switch {
+			case isPlural(a, b) || isPlural(b, a):
+				report.add(KindPlural, pair, "singular and plural forms")
+				groups.union(pair[0], pair[1])
+			case l.isNearDuplicate(a, b):
+				report.add(KindNearDuplicate, pair, fmt.Sprintf("edit distance %d", distance(normalize(a), normalize(b))))
+				near = append(near, pair)
+			}
+		}
+	}
+
+	// near duplicates are not transitive (code, core, care, cars), so only a pair whose groups
+	// have no other near duplicate is merged; the others are left to review
+	partners := map[string]int{}
+	for _, pair := range near {
+		if ra, rb := groups.find(pair[0]), groups.find(pair[1]); ra != rb {
+			partners[ra]++
+			partners[rb]++
+		}
+	}
+	for _, pair := range near {
+		if ra, rb := groups.find(pair[0]), groups.find(pair[1]); ra != rb && partners[ra] == 1 && partners[rb] == 1 {
+			groups.union(ra, rb)
+		}
+	}
+
+	for _, name := range names {
+		if strings.ContainsAny(name, mishandled) || strings.IndexFunc(name, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+			report.add(KindCharacters, []string{name}, fmt.Sprintf("contains one of %s, whitespace or control characters", mishandled))
+		}
+		if counts[name] == 1 {
+			report.add(KindSingleUse, []string{name}, "used by one bookmark")
+		}
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		a, b := report.Issues[i], report.Issues[j]
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		return strings.Join(a.Tags, " ") < strings.Join(b.Tags, " ")
+	})
+
+	report.Mapping = propose(groups, names, counts)
+	return report
+}
+
+// add adds an issue for tags
+func (r *Report) add(kind Kind, tags []string, message string) {
+	sorted := append([]string{}, tags...)
+	sort.Strings(sorted)
+	r.Issues = append(r.Issues, Issue{Kind: kind, Tags: sorted, Message: message})
+}
+
+// propose maps every tag of a group to the group's canonical tag
+func propose(groups *unionFind, names []string, counts map[string]int) remap.Mapping {
+	members := map[string][]string{}
+	for _, name := range names {
+		root := groups.find(name)
+		members[root] = append(members[root], name)
+	}
+
+	mapping := remap.Mapping{}
+	for _, group := range members {
+		if len(group) < 2 {
+			continue
+		}
+		canonical := group[0]
+		for _, name := range group[1:] {
+			if preferred(name, canonical, counts) {
+				canonical = name
+			}
+		}
+		for _, name := range group {
+			if name != canonical {
+				mapping = append(mapping, remap.Rename{Old: name, New: canonical})
+			}
+		}
+	}
+
+	sort.Slice(mapping, func(i, j int) bool {
+		if mapping[i].New != mapping[j].New {
+			return mapping[i].New < mapping[j].New
+		}
+		return mapping[i].Old < mapping[j].Old
+	})
+	return mapping
+}
+
+// preferred reports whether a makes a better canonical tag than b: used more, then lower case, then shorter
+func preferred(a string, b string, counts map[string]int) bool {
+	if counts[a] != counts[b] {
+		return counts[a] > counts[b]
+	}
+	aLower, bLower := a == strings.ToLower(a), b == strings.ToLower(b)
+	if aLower != bLower {
+		return aLower
+	}
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+// isNearDuplicate reports whether the lower case tags a and b are near duplicates
+func (l *linter) isNearDuplicate(a string, b string) bool {
+	a, b = normalize(a), normalize(b)
+	if a == b {
+		return true
+	}
+	shortest := utf8.RuneCountInString(a)
+	if n := utf8.RuneCountInString(b); n < shortest {
+		shortest = n
+	}
+	allowed := (shortest - 1) / 3
+	if allowed > l.maxDistance {
+		allowed = l.maxDistance
+	}
+	return allowed > 0 && distance(a, b) <= allowed
+}
+
+// normalize removes the separators - and _ from a tag
+func normalize(tag string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(tag)
+}
+
+// isPlural reports whether the lower case tag plural is the plural of singular
+func isPlural(singular string, plural string) bool {
+	if utf8.RuneCountInString(singular) < 3 {
+		return false
+	}
+	if plural == singular+"s" || plural == singular+"es" {
+		return true
+	}
+	return strings.HasSuffix(singular, "y") && plural == strings.TrimSuffix(singular, "y")+"ies"
+}
+
+// distance returns the Levenshtein distance between a and b
+func distance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = minOf(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// minOf returns the smallest of values
+func minOf(values ...int) int {
+	smallest := values[0]
+	for _, value := range values[1:] {
+		if value < smallest {
+			smallest = value
+		}
+	}
+	return smallest
+}
+
+// unionFind groups related tags
+type unionFind struct {
+	// parent. the parent of each tag; roots are their own parent
+	parent map[string]string
+}
+
+// newUnionFind returns a unionFind with every name in its own group
+func newUnionFind(names []string) *unionFind {
+	u := &unionFind{parent: make(map[string]string, len(names))}
+	for _, name := range names {
+		u.parent[name] = name
+	}
+	return u
+}
+
+// find returns the root of the group of name
+func (u *unionFind) find(name string) string {
+	for u.parent[name] != name {
+		u.parent[name] = u.parent[u.parent[name]]
+		name = u.parent[name]
+	}
+	return name
+}
+
+// union merges the groups of a and b
+func (u *unionFind) union(a string, b string) {
+	ra, rb := u.find(a), u.find(b)
+	if ra != rb {
+		u.parent[rb] = ra
+	}
+}
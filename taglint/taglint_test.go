@@ -0,0 +1,113 @@
+package taglint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rmrfslashbin/thumbtack/remap"
+)
+
+// TestLint tests the issues found and the proposed mapping
+func TestLint(t *testing.T) {
+	tags := &thumbtack.Tags{Tags: map[string]int{
+		"Golang":      2,
+		"golang":      3,
+		"go-lang":     1,
+		"go":          9,
+		"js":          4,
+		"library":     5,
+		"libraries":   2,
+		"javascript":  7,
+		"javascipt":   2,
+		"c++":         3,
+		"via:popular": 1,
+	}}
+
+	report := Lint(tags)
+	want := []Issue{
+		{Kind: KindCase, Tags: []string{"Golang", "golang"}},
+		{Kind: KindNearDuplicate, Tags: []string{"Golang", "go-lang"}},
+		{Kind: KindNearDuplicate, Tags: []string{"javascipt", "javascript"}},
+		{Kind: KindPlural, Tags: []string{"libraries", "library"}},
+		{Kind: KindCharacters, Tags: []string{"c++"}},
+		{Kind: KindSingleUse, Tags: []string{"go-lang"}},
+	}
+	if len(report.Issues) != len(want) {
+		t.Fatalf("expected %d issues, got %+v", len(want), report.Issues)
+	}
+	for i, issue := range report.Issues {
+		if issue.Kind != want[i].Kind || !reflect.DeepEqual(issue.Tags, want[i].Tags) || issue.Message == "" {
+			t.Errorf("issue %d: expected %+v, got %+v", i, want[i], issue)
+		}
+	}
+
+	mapping := remap.Mapping{
+		{Old: "Golang", New: "golang"},
+		{Old: "go-lang", New: "golang"},
+		{Old: "javascipt", New: "javascript"},
+		{Old: "libraries", New: "library"},
+	}
+	if !reflect.DeepEqual(report.Mapping, mapping) {
+		t.Errorf("expected mapping %v, got %v", mapping, report.Mapping)
+	}
+
+	// the proposal is a valid plan without conflicts other than merges into the kept tags
+	if _, err := remap.NewPlan(report.Mapping, tags); err != nil {
+		t.Errorf("expected the proposed mapping to plan, got %v", err)
+	}
+}
+
+// TestLintNearDuplicateChain tests that a chain of near duplicates is reported but not merged
+func TestLintNearDuplicateChain(t *testing.T) {
+	tags := &thumbtack.Tags{Tags: map[string]int{
+		"code": 5, "core": 3, "care": 2, "cars": 2, "cats": 4,
+		"next": 2, "text": 3, "test": 6,
+		"golang": 4, "go-lang": 2, "Golang": 1,
+		"book": 3, "books": 2, "boks": 1,
+	}}
+
+	report := Lint(tags)
+	near := 0
+	for _, issue := range report.Issues {
+		if issue.Kind == KindNearDuplicate {
+			near++
+		}
+	}
+	if near < 6 {
+		t.Errorf("expected the chains to be reported, got %+v", report.Issues)
+	}
+
+	// boks is only near books, so it joins the book group
+	mapping := remap.Mapping{
+		{Old: "boks", New: "book"},
+		{Old: "books", New: "book"},
+		{Old: "Golang", New: "golang"},
+		{Old: "go-lang", New: "golang"},
+	}
+	if !reflect.DeepEqual(report.Mapping, mapping) {
+		t.Errorf("expected mapping %v, got %v", mapping, report.Mapping)
+	}
+}
+
+// TestLintMaxDistance tests limiting near duplicates
+func TestLintMaxDistance(t *testing.T) {
+	tags := &thumbtack.Tags{Tags: map[string]int{"javascript": 2, "javascipt": 2, "go_lang": 2, "golang": 2, "rust": 2, "dust": 2}}
+
+	report := Lint(tags, WithMaxDistance(0))
+	if len(report.Issues) != 1 || report.Issues[0].Kind != KindNearDuplicate || report.Issues[0].Tags[0] != "go_lang" {
+		t.Errorf("expected only go_lang and golang, got %+v", report.Issues)
+	}
+}
+
+// TestDistance tests the edit distance
+func TestDistance(t *testing.T) {
+	for _, test := range []struct {
+		a, b string
+		want int
+	}{{"", "abc", 3}, {"kitten", "sitting", 3}, {"golang", "golang", 0}, {"café", "cafe", 1}} {
+		if got := distance(test.a, test.b); got != test.want {
+			t.Errorf("distance(%q, %q): expected %d, got %d", test.a, test.b, test.want, got)
+		}
+	}
+}
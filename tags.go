@@ -1,6 +1,7 @@
 package thumbtack
 
 import (
+	"context"
 	"encoding/json"
 	"net/url"
 )
@@ -8,6 +9,11 @@ import (
 // TagsDelete deletes a tag from the user's account
 // https://pinboard.in/api/#tags_delete
 func (c *Client) TagsDelete(tag string) (*Result, error) {
+	return c.TagsDeleteWithContext(context.Background(), tag)
+}
+
+// TagsDeleteWithContext is the same as TagsDelete, but uses ctx for cancellation and deadlines.
+func (c *Client) TagsDeleteWithContext(ctx context.Context, tag string) (*Result, error) {
 	// Set up the query parameters
 	v := url.Values{}
 	v.Set("format", c.format)
@@ -19,13 +25,13 @@ func (c *Client) TagsDelete(tag string) (*Result, error) {
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.callEndpoint(tagsDelete, v.Encode())
+	body, err := c.callEndpoint(ctx, "TagsDelete", tagsDelete, v.Encode())
 	if err != nil {
 		c.log.Error().
 			Str("function", "thumbtack::TagsDelete").
 			Str("endpoint", c.endpoint.String()).
 			Str("path", tagsDelete).
-			Str("query", v.Encode()).
+			Str("query", c.redact(v.Encode())).
 			Msg("error calling endpoint")
 		return nil, err
 	}
@@ -36,13 +42,14 @@ func (c *Client) TagsDelete(tag string) (*Result, error) {
 	if err != nil {
 		c.log.Error().Msg("error unmarshalling response")
 		return nil, &ErrUnmarshalResponse{
+			Api:  "TagsDelete",
 			Body: *body,
 			Err:  err,
 		}
 	}
 
 	if result.Result != "done" {
-		return nil, &ErrUnexpectedResponse{ResultCode: result.Result}
+		return nil, &ErrUnexpectedResponse{Api: "TagsDelete", ResultCode: result.Result}
 	}
 
 	return result, nil
@@ -51,6 +58,11 @@ func (c *Client) TagsDelete(tag string) (*Result, error) {
 // TagsGet returns a full list of the user's tags along with the number of times they were used.
 // https://pinboard.in/api/#tags_get
 func (c *Client) TagsGet() (*Tags, error) {
+	return c.TagsGetWithContext(context.Background())
+}
+
+// TagsGetWithContext is the same as TagsGet, but uses ctx for cancellation and deadlines.
+func (c *Client) TagsGetWithContext(ctx context.Context) (*Tags, error) {
 	// Set up the query parameters
 	v := url.Values{}
 	v.Set("format", c.format)
@@ -61,13 +73,13 @@ func (c *Client) TagsGet() (*Tags, error) {
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.callEndpoint(tagsGet, v.Encode())
+	body, err := c.callEndpoint(ctx, "TagsGet", tagsGet, v.Encode())
 	if err != nil {
 		c.log.Error().
 			Str("function", "thumbtack::TagsGet").
 			Str("endpoint", c.endpoint.String()).
 			Str("path", tagsGet).
-			Str("query", v.Encode()).
+			Str("query", c.redact(v.Encode())).
 			Msg("error calling endpoint")
 		return nil, err
 	}
@@ -78,6 +90,7 @@ func (c *Client) TagsGet() (*Tags, error) {
 	if err != nil {
 		c.log.Error().Msg("error unmarshalling response")
 		return nil, &ErrUnmarshalResponse{
+			Api:  "TagsGet",
 			Body: *body,
 			Err:  err,
 		}
@@ -98,6 +111,11 @@ type TagsRenameInput struct {
 // TagsRename renames a tag
 // https://pinboard.in/api/#tags_rename
 func (c *Client) TagsRename(input *TagsRenameInput) (*Result, error) {
+	return c.TagsRenameWithContext(context.Background(), input)
+}
+
+// TagsRenameWithContext is the same as TagsRename, but uses ctx for cancellation and deadlines.
+func (c *Client) TagsRenameWithContext(ctx context.Context, input *TagsRenameInput) (*Result, error) {
 	if input == nil {
 		return nil, &ErrInvalidInput{}
 	}
@@ -122,13 +140,13 @@ func (c *Client) TagsRename(input *TagsRenameInput) (*Result, error) {
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.callEndpoint(tagsRename, v.Encode())
+	body, err := c.callEndpoint(ctx, "TagsRename", tagsRename, v.Encode())
 	if err != nil {
 		c.log.Error().
 			Str("function", "thumbtack::TagsRename").
 			Str("endpoint", c.endpoint.String()).
 			Str("path", tagsRename).
-			Str("query", v.Encode()).
+			Str("query", c.redact(v.Encode())).
 			Msg("error calling endpoint")
 		return nil, err
 	}
@@ -139,13 +157,14 @@ func (c *Client) TagsRename(input *TagsRenameInput) (*Result, error) {
 	if err != nil {
 		c.log.Error().Msg("error unmarshalling response")
 		return nil, &ErrUnmarshalResponse{
+			Api:  "TagsRename",
 			Body: *body,
 			Err:  err,
 		}
 	}
 
 	if result.Result != "done" {
-		return nil, &ErrUnexpectedResponse{ResultCode: result.Result}
+		return nil, &ErrUnexpectedResponse{Api: "TagsRename", ResultCode: result.Result}
 	}
 
 	return result, nil
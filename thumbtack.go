@@ -3,8 +3,10 @@ package thumbtack
 // https://pinboard.in/api/
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -16,6 +18,11 @@ import (
 // Options for the controller query
 type Option func(c *Client)
 
+// DefaultTimeout is the dial, TLS handshake and response header timeout of the transport used when
+// no http client is provided. Reading the response body is not limited, so large posts/all
+// responses and streams are not cut off; use a context deadline to bound a whole call.
+const DefaultTimeout = 30 * time.Second
+
 // Client provides access to the Thumbtack API
 type Client struct {
 	// configs. the configs for the controller
@@ -30,9 +37,21 @@ type Client struct {
 	// format. the format of the response. format is always json
 	format string
 
+	// httpClient. the http client used for all requests. shared so connections are reused
+	httpClient *http.Client
+
+	// limiter. if provided, every call waits on the limiter before the request is sent
+	limiter *RateLimiter
+
 	// logger. if not provided, a default logger will be used
 	log *zerolog.Logger
 
+	// retryPolicy. if provided, failed calls are retried according to the policy
+	retryPolicy *RetryPolicy
+
+	// transport. if provided, replaces the RoundTripper of the http client
+	transport http.RoundTripper
+
 	// token. the token is required for all requests
 	token *string
 
@@ -65,6 +84,18 @@ func New(opts ...Option) (*Client, error) {
 		client.configs = NewConfig()
 	}
 
+	// set up http client if not provided
+	if client.httpClient == nil {
+		client.httpClient = &http.Client{Transport: defaultTransport()}
+	}
+
+	// set up the transport if provided, on a copy so a client passed to WithHTTPClient is not modified
+	if client.transport != nil {
+		httpClient := *client.httpClient
+		httpClient.Transport = client.transport
+		client.httpClient = &httpClient
+	}
+
 	// set up token if not provided
 	if client.token == nil {
 		return nil, &ErrNoToken{}
@@ -98,6 +129,15 @@ func WithEndpoint(endpoint *url.URL) Option {
 	}
 }
 
+// WithHTTPClient sets the http client used for all requests.
+// The client is used as is; its Timeout is not changed. A transport set with WithTransport
+// replaces the client's transport, whatever the order of the options.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
 // WithLogger sets the logger for the controller
 func WithLogger(log *zerolog.Logger) Option {
 	return func(c *Client) {
@@ -105,6 +145,13 @@ func WithLogger(log *zerolog.Logger) Option {
 	}
 }
 
+// WithRateLimiter sets the rate limiter for the controller
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
 // WithToken sets the token for the controller
 func WithToken(token *string) Option {
 	return func(c *Client) {
@@ -112,6 +159,15 @@ func WithToken(token *string) Option {
 	}
 }
 
+// WithTransport sets the RoundTripper of the http client.
+// If no http client is provided, one without a timeout is created for the transport;
+// a client passed to WithHTTPClient is copied, not modified.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
+
 // WithUserAgent sets the userAgent for the controller
 func WithUserAgent(userAgent *string) Option {
 	return func(c *Client) {
@@ -119,42 +175,169 @@ func WithUserAgent(userAgent *string) Option {
 	}
 }
 
-// callEndpoint calls the endpoint and returns the response body
-func (c *Client) callEndpoint(path string, query string) (*[]byte, error) {
+// defaultTransport returns the transport of the default http client: http.DefaultTransport
+// with DefaultTimeout for dialing, the TLS handshake and the response headers
+func defaultTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   DefaultTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = DefaultTimeout
+	transport.ResponseHeaderTimeout = DefaultTimeout
+	return transport
+}
+
+// callEndpoint calls the endpoint and returns the response body.
+// api is the name of the api being called (e.g. "PostsAll") and is used for rate limiting.
+// The request is bound to ctx; if ctx is canceled or its deadline passes, ErrRequestCanceled is returned.
+// Failed calls are retried according to the client's RetryPolicy; if every attempt fails,
+// or an attempt after the first fails with an error that is not retried, ErrRetriesExhausted wraps the last error.
+func (c *Client) callEndpoint(ctx context.Context, api string, path string, query string) (*[]byte, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
 	url := fmt.Sprintf("%s%s?%s", c.endpoint.String(), path, query)
-	c.log.Debug().
-		Str("url", url).
-		Msg("calling endpoint")
 
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", url, nil)
+	var body *[]byte
+	err := c.withRetries(ctx, api, func() (time.Duration, error) {
+		var retryAfter time.Duration
+		var err error
+		body, retryAfter, err = c.callEndpointOnce(ctx, api, url)
+		return retryAfter, err
+	})
 	if err != nil {
-		c.log.Error().Msg("failed to create request")
 		return nil, err
 	}
+	return body, nil
+}
 
-	req.Header.Add("User-Agent", *c.userAgent)
+// openEndpoint calls the endpoint like callEndpoint, but returns the response body unread.
+// The caller must close the body. Errors while reading the body are not retried.
+func (c *Client) openEndpoint(ctx context.Context, api string, path string, query string) (io.ReadCloser, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
-	res, err := client.Do(req)
+	url := fmt.Sprintf("%s%s?%s", c.endpoint.String(), path, query)
+
+	var res *http.Response
+	err := c.withRetries(ctx, api, func() (time.Duration, error) {
+		var retryAfter time.Duration
+		var err error
+		res, retryAfter, err = c.openEndpointOnce(ctx, api, url)
+		return retryAfter, err
+	})
 	if err != nil {
-		c.log.Error().Msg("failed to call endpoint")
 		return nil, err
 	}
+	return res.Body, nil
+}
+
+// withRetries runs call until it succeeds or the client's RetryPolicy gives up.
+// call returns the delay requested by the server's Retry-After header along with its error.
+func (c *Client) withRetries(ctx context.Context, api string, call func() (time.Duration, error)) error {
+	attempts := c.retryPolicy.attempts()
+
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := call()
+		if err == nil {
+			return nil
+		}
+
+		err = setApi(err, api)
+		if attempts < 2 || !c.retryPolicy.retryable(err) {
+			if attempt > 1 {
+				// keep the number of attempts made before the error that could not be retried
+				return &ErrRetriesExhausted{Api: api, Attempts: attempt, Err: err}
+			}
+			return err
+		}
+		if attempt >= attempts {
+			return &ErrRetriesExhausted{Api: api, Attempts: attempt, Err: err}
+		}
+
+		delay := c.retryPolicy.backoff(attempt, retryAfter)
+		c.log.Warn().
+			Err(err).
+			Str("api", api).
+			Int("attempt", attempt).
+			Dur("delay", delay).
+			Msg("retrying call")
+		if err := sleep(ctx, delay); err != nil {
+			return setApi(err, api)
+		}
+	}
+}
+
+// callEndpointOnce makes a single call to the endpoint and reads the response body.
+// It returns the response body, or an error and the delay requested by the server's Retry-After header.
+func (c *Client) callEndpointOnce(ctx context.Context, api string, url string) (*[]byte, time.Duration, error) {
+	res, retryAfter, err := c.openEndpointOnce(ctx, api, url)
+	if err != nil {
+		return nil, retryAfter, err
+	}
 	defer res.Body.Close()
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
+		if ctx.Err() != nil {
+			c.log.Error().Msg("request canceled")
+			return nil, 0, &ErrRequestCanceled{Err: ctx.Err()}
+		}
 		c.log.Error().Msg("failed to read response body")
-		return nil, err
+		return nil, 0, c.redactError(err)
+	}
+
+	return &body, 0, nil
+}
+
+// openEndpointOnce makes a single call to the endpoint and checks the status code.
+// The auth token is redacted from everything it logs and from the errors it returns.
+// It returns the response, or an error and the delay requested by the server's Retry-After header.
+func (c *Client) openEndpointOnce(ctx context.Context, api string, url string) (*http.Response, time.Duration, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx, api); err != nil {
+			c.log.Error().
+				Str("api", api).
+				Msg("rate limiter refused call")
+			return nil, 0, err
+		}
+	}
+
+	c.log.Debug().
+		Str("url", c.redact(url)).
+		Msg("calling endpoint")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		c.log.Error().Msg("failed to create request")
+		return nil, 0, &ErrBadEndpoint{Msg: "failed to create request", Err: c.redactError(err)}
+	}
+
+	req.Header.Add("User-Agent", *c.userAgent)
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			c.log.Error().Msg("request canceled")
+			return nil, 0, &ErrRequestCanceled{Err: ctx.Err()}
+		}
+		c.log.Error().Msg("failed to call endpoint")
+		return nil, 0, c.redactError(err)
 	}
 
 	// check status code and return error if not 200
 	if res.StatusCode != 200 {
-		return nil, &ErrBadStatusCode{
+		// drain a little of the body so the connection can be reused
+		io.Copy(io.Discard, io.LimitReader(res.Body, 4096))
+		res.Body.Close()
+		return nil, parseRetryAfter(res.Header.Get("Retry-After"), time.Now()), &ErrBadStatusCode{
 			StatusCode: res.StatusCode,
 			Status:     res.Status,
 		}
 	}
 
-	return &body, nil
+	return res, 0, nil
 }
@@ -1,11 +1,15 @@
 package thumbtack
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
 )
@@ -162,3 +166,306 @@ func TestThumbtackWithConfig(t *testing.T) {
 		t.Fatalf("expected error to be ErrBadStatusCode, got %v", v)
 	}
 }
+
+func TestThumbtackContextCanceled(t *testing.T) {
+	token := "test:abc123"
+	useragent := "test/1.0"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":"abc123"}`))
+	}))
+	defer ts.Close()
+
+	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(zerolog.PanicLevel)
+	url, _ := url.Parse(ts.URL)
+
+	client, err := New(
+		WithEndpoint(url),
+		WithToken(&token),
+		WithLogger(&log),
+		WithUserAgent(&useragent),
+	)
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = client.UserSecretWithContext(ctx)
+	if _, ok := err.(*ErrRequestCanceled); !ok {
+		t.Fatalf("expected error to be ErrRequestCanceled, got %T", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected error to wrap context.Canceled, got %v", err)
+	}
+}
+
+func TestThumbtackContextDeadline(t *testing.T) {
+	token := "test:abc123"
+	useragent := "test/1.0"
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(zerolog.PanicLevel)
+	url, _ := url.Parse(ts.URL)
+
+	client, err := New(
+		WithEndpoint(url),
+		WithToken(&token),
+		WithLogger(&log),
+		WithUserAgent(&useragent),
+	)
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = client.PostsAllWithContext(ctx, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected error to wrap context.DeadlineExceeded, got %v", err)
+	}
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestThumbtackDefaultHTTPClient(t *testing.T) {
+	token := "test:abc123"
+
+	client, err := New(WithToken(&token))
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+	if client.httpClient == nil {
+		t.Fatalf("expected httpClient to not be nil")
+	}
+	if client.httpClient.Timeout != 0 {
+		t.Errorf("expected no client timeout, got %s", client.httpClient.Timeout)
+	}
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected an *http.Transport, got %T", client.httpClient.Transport)
+	}
+	if transport.ResponseHeaderTimeout != DefaultTimeout || transport.TLSHandshakeTimeout != DefaultTimeout {
+		t.Errorf("expected transport timeouts to be %s, got %s and %s", DefaultTimeout, transport.ResponseHeaderTimeout, transport.TLSHandshakeTimeout)
+	}
+}
+
+func TestThumbtackDefaultHTTPClientSlowBody(t *testing.T) {
+	token := "test:abc123"
+	useragent := "test/1.0"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":`)
+		w.(http.Flusher).Flush()
+		time.Sleep(100 * time.Millisecond)
+		fmt.Fprint(w, `"abc123"}`)
+	}))
+	defer ts.Close()
+
+	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(zerolog.PanicLevel)
+	url, _ := url.Parse(ts.URL)
+
+	client, err := New(
+		WithEndpoint(url),
+		WithToken(&token),
+		WithLogger(&log),
+		WithUserAgent(&useragent),
+	)
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+	// a response header timeout shorter than the body must not cut the body off
+	client.httpClient.Transport.(*http.Transport).ResponseHeaderTimeout = 50 * time.Millisecond
+
+	secret, err := client.UserSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if secret.Result != "abc123" {
+		t.Errorf("expected result 'abc123', got '%s'", secret.Result)
+	}
+}
+
+func TestThumbtackWithTransport(t *testing.T) {
+	token := "test:abc123"
+	useragent := "test/1.0"
+	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(zerolog.PanicLevel)
+
+	calls := 0
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		if r.Header.Get("User-Agent") != useragent {
+			t.Errorf("expected User-Agent to be '%s', got '%s'", useragent, r.Header.Get("User-Agent"))
+		}
+		rec := httptest.NewRecorder()
+		rec.WriteString(`{"result":"abc123"}`)
+		return rec.Result(), nil
+	})
+
+	client, err := New(
+		WithToken(&token),
+		WithLogger(&log),
+		WithUserAgent(&useragent),
+		WithTransport(transport),
+	)
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+	if client.httpClient.Timeout != 0 {
+		t.Errorf("expected no client timeout, got %s", client.httpClient.Timeout)
+	}
+
+	for i := 0; i < 2; i++ {
+		secret, err := client.UserSecret()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if secret.Result != "abc123" {
+			t.Errorf("expected result 'abc123', got '%s'", secret.Result)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls through the transport, got %d", calls)
+	}
+}
+
+func TestThumbtackWithHTTPClientTimeout(t *testing.T) {
+	token := "test:abc123"
+	useragent := "test/1.0"
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(zerolog.PanicLevel)
+	url, _ := url.Parse(ts.URL)
+
+	httpClient := &http.Client{Timeout: 50 * time.Millisecond}
+	client, err := New(
+		WithEndpoint(url),
+		WithToken(&token),
+		WithLogger(&log),
+		WithUserAgent(&useragent),
+		WithHTTPClient(httpClient),
+	)
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+	if client.httpClient != httpClient {
+		t.Fatalf("expected the provided http client to be used")
+	}
+
+	_, err = client.UserSecret()
+	if err == nil {
+		t.Fatalf("expected error to not be nil")
+	}
+	if _, ok := err.(*ErrRequestCanceled); ok {
+		t.Fatalf("expected a timeout error, not ErrRequestCanceled")
+	}
+}
+
+func TestThumbtackWithTransportCopiesHTTPClient(t *testing.T) {
+	token := "test:abc123"
+	httpClient := &http.Client{Timeout: time.Second}
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, errors.New("not implemented")
+	})
+
+	client, err := New(
+		WithToken(&token),
+		WithHTTPClient(httpClient),
+		WithTransport(transport),
+	)
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+	if httpClient.Transport != nil {
+		t.Errorf("expected the provided http client to not be modified")
+	}
+	if client.httpClient.Timeout != time.Second {
+		t.Errorf("expected timeout to be 1s, got %s", client.httpClient.Timeout)
+	}
+}
+
+func TestThumbtackWithHTTPClientAfterTransport(t *testing.T) {
+	token := "test:abc123"
+	httpClient := &http.Client{Timeout: time.Second}
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, errors.New("not implemented")
+	})
+
+	client, err := New(
+		WithToken(&token),
+		WithTransport(transport),
+		WithHTTPClient(httpClient),
+	)
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+	if _, ok := client.httpClient.Transport.(roundTripperFunc); !ok {
+		t.Errorf("expected the transport to be kept, got %T", client.httpClient.Transport)
+	}
+	if httpClient.Transport != nil {
+		t.Errorf("expected the provided http client to not be modified")
+	}
+	if client.httpClient.Timeout != time.Second {
+		t.Errorf("expected timeout to be 1s, got %s", client.httpClient.Timeout)
+	}
+}
+
+func TestThumbtackUnauthorized(t *testing.T) {
+	token := "test:abc123"
+	useragent := "test/1.0"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(zerolog.PanicLevel)
+	url, _ := url.Parse(ts.URL)
+
+	client, err := New(
+		WithEndpoint(url),
+		WithToken(&token),
+		WithLogger(&log),
+		WithUserAgent(&useragent),
+	)
+	if err != nil {
+		t.Fatalf("failed to create thumbtask instance: %v", err)
+	}
+
+	_, err = client.TagsGet()
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected error to match ErrUnauthorized, got %v", err)
+	}
+	if api := ApiOf(err); api != "TagsGet" {
+		t.Errorf("expected api to be 'TagsGet', got '%s'", api)
+	}
+	if code := StatusCodeOf(err); code != http.StatusUnauthorized {
+		t.Errorf("expected status code to be 401, got %d", code)
+	}
+}
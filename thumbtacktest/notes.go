@@ -0,0 +1,67 @@
+package thumbtacktest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// wireNote is a note in the Pinboard wire format
+type wireNote struct {
+	Id        string  `json:"id"`
+	Hash      string  `json:"hash"`
+	Title     string  `json:"title"`
+	Length    float64 `json:"length"`
+	Text      string  `json:"text,omitempty"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+}
+
+// toWireNote converts note to the Pinboard wire format. The text is only included if withText is true.
+func toWireNote(note thumbtack.Note, withText bool) wireNote {
+	wire := wireNote{
+		Id:        note.Id,
+		Hash:      note.Hash,
+		Title:     note.Title,
+		Length:    note.Length,
+		CreatedAt: note.CreatedAt.UTC().Format(time.DateTime),
+		UpdatedAt: note.UpdatedAt.UTC().Format(time.DateTime),
+	}
+	if withText {
+		wire.Text = note.Text
+	}
+	return wire
+}
+
+// handleNotesById emulates notes/ID
+func (s *Server) handleNotesById(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/notes/")
+	note, ok := s.notes[id]
+	if !ok || id == "" {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, toWireNote(note, true))
+}
+
+// handleNotesList emulates notes/list
+func (s *Server) handleNotesList(w http.ResponseWriter, r *http.Request) {
+	notes := make([]wireNote, 0, len(s.notes))
+	for _, note := range s.notes {
+		notes = append(notes, toWireNote(note, false))
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		if notes[i].UpdatedAt != notes[j].UpdatedAt {
+			return notes[i].UpdatedAt > notes[j].UpdatedAt
+		}
+		return notes[i].Id < notes[j].Id
+	})
+
+	writeJSON(w, map[string]interface{}{
+		"count": len(notes),
+		"notes": notes,
+	})
+}
@@ -0,0 +1,346 @@
+package thumbtacktest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// wireBookmark is a bookmark in the Pinboard wire format
+type wireBookmark struct {
+	Href        string `json:"href"`
+	Description string `json:"description"`
+	Extended    string `json:"extended"`
+	Meta        string `json:"meta,omitempty"`
+	Hash        string `json:"hash"`
+	Time        string `json:"time"`
+	Shared      string `json:"shared"`
+	ToRead      string `json:"toread"`
+	Tags        string `json:"tags"`
+}
+
+// toWire converts bookmark to the Pinboard wire format. meta is only included if withMeta is true.
+func toWire(bookmark thumbtack.Bookmark, withMeta bool) wireBookmark {
+	wire := wireBookmark{
+		Href:        bookmark.Href,
+		Description: bookmark.Description,
+		Extended:    bookmark.Extended,
+		Hash:        bookmark.Hash,
+		Time:        bookmark.Time.UTC().Format(time.RFC3339),
+		Shared:      yesNo(bookmark.Shared),
+		ToRead:      yesNo(bookmark.ToRead),
+		Tags:        strings.Join(bookmark.Tags, " "),
+	}
+	if withMeta {
+		wire.Meta = bookmark.Meta
+	}
+	return wire
+}
+
+// toWireList converts bookmarks to the Pinboard wire format
+func toWireList(bookmarks []thumbtack.Bookmark, withMeta bool) []wireBookmark {
+	wire := make([]wireBookmark, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		wire = append(wire, toWire(bookmark, withMeta))
+	}
+	return wire
+}
+
+// filterTags returns the bookmarks with all tags
+func filterTags(bookmarks []thumbtack.Bookmark, tags []string) []thumbtack.Bookmark {
+	if len(tags) == 0 {
+		return bookmarks
+	}
+	filtered := []thumbtack.Bookmark{}
+	for _, bookmark := range bookmarks {
+		if bookmark.HasTags(tags...) {
+			filtered = append(filtered, bookmark)
+		}
+	}
+	return filtered
+}
+
+// parseTime parses a datetime parameter
+func parseTime(value string) (time.Time, error) {
+	return time.Parse(time.RFC3339, value)
+}
+
+// postsResponse is the response of posts/get and posts/recent
+type postsResponse struct {
+	Date  string         `json:"date"`
+	User  string         `json:"user"`
+	Posts []wireBookmark `json:"posts"`
+}
+
+// handlePostsAdd emulates posts/add
+func (s *Server) handlePostsAdd(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	href := q.Get("url")
+	if href == "" {
+		writeResultCode(w, "missing url")
+		return
+	}
+	if _, err := url.ParseRequestURI(href); err != nil {
+		writeResultCode(w, "missing url")
+		return
+	}
+	title := q.Get("description")
+	if title == "" {
+		writeResultCode(w, "must provide title")
+		return
+	}
+
+	existing, exists := s.bookmarks[href]
+	if exists && q.Get("replace") == "no" {
+		writeResultCode(w, "item already exists")
+		return
+	}
+
+	tags := splitTags(q.Get("tags"))
+	if len(tags) > 100 {
+		writeResultCode(w, "something went wrong")
+		return
+	}
+
+	bookmark := thumbtack.Bookmark{
+		Href:        href,
+		Description: title,
+		Extended:    q.Get("extended"),
+		Shared:      q.Get("shared") != "no",
+		ToRead:      q.Get("toread") == "yes",
+		Tags:        tags,
+	}
+	if exists {
+		// replacing keeps the original creation time unless a new one is given
+		bookmark.Time = existing.Time
+	}
+	if dt := q.Get("dt"); dt != "" {
+		timestamp, err := parseTime(dt)
+		if err != nil {
+			writeResultCode(w, "something went wrong")
+			return
+		}
+		if timestamp.After(s.now().Add(10 * time.Minute)) {
+			timestamp = s.now()
+		}
+		bookmark.Time = timestamp
+	}
+
+	s.store(bookmark)
+	writeResultCode(w, "done")
+}
+
+// handlePostsAll emulates posts/all
+func (s *Server) handlePostsAll(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	bookmarks := filterTags(s.sorted(), splitTags(q.Get("tag")))
+
+	if fromdt := q.Get("fromdt"); fromdt != "" {
+		from, err := parseTime(fromdt)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		filtered := []thumbtack.Bookmark{}
+		for _, bookmark := range bookmarks {
+			if !bookmark.Time.Before(from) {
+				filtered = append(filtered, bookmark)
+			}
+		}
+		bookmarks = filtered
+	}
+
+	if todt := q.Get("todt"); todt != "" {
+		to, err := parseTime(todt)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		filtered := []thumbtack.Bookmark{}
+		for _, bookmark := range bookmarks {
+			if !bookmark.Time.After(to) {
+				filtered = append(filtered, bookmark)
+			}
+		}
+		bookmarks = filtered
+	}
+
+	if start, err := strconv.Atoi(q.Get("start")); err == nil && start > 0 {
+		if start > len(bookmarks) {
+			start = len(bookmarks)
+		}
+		bookmarks = bookmarks[start:]
+	}
+
+	if results, err := strconv.Atoi(q.Get("results")); err == nil && results >= 0 && results < len(bookmarks) {
+		bookmarks = bookmarks[:results]
+	}
+
+	writeJSON(w, toWireList(bookmarks, q.Get("meta") == "yes"))
+}
+
+// handlePostsDates emulates posts/dates
+func (s *Server) handlePostsDates(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	dates := map[string]int{}
+	for _, bookmark := range filterTags(s.sorted(), splitTags(q.Get("tag"))) {
+		dates[bookmark.Time.Format(time.DateOnly)]++
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"user":  s.user,
+		"tag":   q.Get("tag"),
+		"dates": dates,
+	})
+}
+
+// handlePostsDelete emulates posts/delete
+func (s *Server) handlePostsDelete(w http.ResponseWriter, r *http.Request) {
+	href := r.URL.Query().Get("url")
+	if href == "" {
+		writeResultCode(w, "missing url")
+		return
+	}
+	if _, ok := s.bookmarks[href]; !ok {
+		writeResultCode(w, "item not found")
+		return
+	}
+
+	delete(s.bookmarks, href)
+	s.touch()
+	writeResultCode(w, "done")
+}
+
+// handlePostsGet emulates posts/get
+func (s *Server) handlePostsGet(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	bookmarks := filterTags(s.sorted(), splitTags(q.Get("tag")))
+	date := ""
+
+	if href := q.Get("url"); href != "" {
+		filtered := []thumbtack.Bookmark{}
+		for _, bookmark := range bookmarks {
+			if bookmark.Href == href {
+				filtered = append(filtered, bookmark)
+			}
+		}
+		bookmarks = filtered
+		if len(bookmarks) > 0 {
+			date = bookmarks[0].Time.Format(time.RFC3339)
+		}
+	} else {
+		// without a date, use the date of the most recent bookmark
+		dt := q.Get("dt")
+		if dt == "" && len(bookmarks) > 0 {
+			dt = bookmarks[0].Time.Format(time.DateOnly)
+		}
+		day, err := time.Parse(time.DateOnly, dt)
+		if err != nil && dt != "" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		filtered := []thumbtack.Bookmark{}
+		for _, bookmark := range bookmarks {
+			if bookmark.Time.Format(time.DateOnly) == dt {
+				filtered = append(filtered, bookmark)
+			}
+		}
+		bookmarks = filtered
+		if dt != "" {
+			date = day.Format(time.RFC3339)
+		}
+	}
+
+	if date == "" {
+		date = s.now().Format(time.RFC3339)
+	}
+
+	writeJSON(w, postsResponse{
+		Date:  date,
+		User:  s.user,
+		Posts: toWireList(bookmarks, q.Get("meta") != "no"),
+	})
+}
+
+// handlePostsRecent emulates posts/recent
+func (s *Server) handlePostsRecent(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	count := 15
+	if c, err := strconv.Atoi(q.Get("count")); err == nil {
+		count = c
+	}
+	if count > 100 {
+		count = 100
+	}
+	if count < 0 {
+		count = 0
+	}
+
+	bookmarks := filterTags(s.sorted(), splitTags(q.Get("tag")))
+	if len(bookmarks) > count {
+		bookmarks = bookmarks[:count]
+	}
+
+	date := s.now().Format(time.RFC3339)
+	if len(bookmarks) > 0 {
+		date = bookmarks[0].Time.Format(time.RFC3339)
+	}
+
+	writeJSON(w, postsResponse{
+		Date:  date,
+		User:  s.user,
+		Posts: toWireList(bookmarks, false),
+	})
+}
+
+// handlePostsSuggest emulates posts/suggest.
+// Popular tags are the tags of the bookmark for the url, recommended tags are the
+// tags used on other bookmarks of the same host.
+func (s *Server) handlePostsSuggest(w http.ResponseWriter, r *http.Request) {
+	href := r.URL.Query().Get("url")
+	if href == "" {
+		writeResultCode(w, "missing url")
+		return
+	}
+
+	popular := []string{}
+	if bookmark, ok := s.bookmarks[href]; ok {
+		popular = append(popular, bookmark.Tags...)
+	}
+
+	recommended := []string{}
+	seen := map[string]bool{}
+	if target, err := url.Parse(href); err == nil {
+		for _, bookmark := range s.sorted() {
+			u, err := url.Parse(bookmark.Href)
+			if err != nil || u.Host != target.Host || bookmark.Href == href {
+				continue
+			}
+			for _, tag := range bookmark.Tags {
+				if !seen[tag] {
+					seen[tag] = true
+					recommended = append(recommended, tag)
+				}
+			}
+		}
+	}
+
+	writeJSON(w, []map[string][]string{
+		{"popular": popular},
+		{"recommended": recommended},
+	})
+}
+
+// handlePostsUpdate emulates posts/update
+func (s *Server) handlePostsUpdate(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]string{"update_time": s.updateTime.Format(time.RFC3339)})
+}
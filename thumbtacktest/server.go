@@ -0,0 +1,341 @@
+// Package thumbtacktest provides an in-memory fake of the Pinboard v1 API for tests.
+//
+// The fake keeps state: posts/add stores bookmarks, posts/get and posts/all filter them,
+// tags/rename rewrites them and posts/update advances with every change. Result codes
+// match the ones returned by the real server.
+//
+//	srv := thumbtacktest.NewServer("user:SECRET")
+//	defer srv.Close()
+//	client, err := srv.Client()
+//
+// In tests, srv.QuietClient(t) returns a client that logs nothing.
+package thumbtacktest
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+	"github.com/rs/zerolog"
+)
+
+// Server is an in-memory fake Pinboard API server.
+// It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	// bookmarks. the stored bookmarks, keyed by url
+	bookmarks map[string]thumbtack.Bookmark
+
+	// failures. status codes returned by the next requests, in order
+	failures []int
+
+	// matchFailures. failures for the next request matching each rule, in the order added
+	matchFailures []matchFailure
+
+	// mu. guards the state of the server
+	mu sync.Mutex
+
+	// notes. the stored notes, keyed by id
+	notes map[string]thumbtack.Note
+
+	// now. returns the current time; overridable for tests
+	now func() time.Time
+
+	// requests. the number of requests served
+	requests int
+
+	// secret. the user's secret RSS key returned by user/secret
+	secret string
+
+	// token. the auth token accepted by the server
+	token string
+
+	// updateTime. the time of the last change
+	updateTime time.Time
+
+	// user. the user name, the part of the token before the colon
+	user string
+}
+
+// matchFailure fails the next request to path whose query has param set to value
+type matchFailure struct {
+	// path. the api path, e.g. /posts/add
+	path string
+
+	// param. the query parameter to match
+	param string
+
+	// value. the value of param to match
+	value string
+
+	// statusCode. the http status code returned
+	statusCode int
+}
+
+// NewServer starts a fake Pinboard server accepting token ("username:TOKEN").
+// Call Close when done.
+func NewServer(token string) *Server {
+	s := &Server{
+		bookmarks: map[string]thumbtack.Bookmark{},
+		notes:     map[string]thumbtack.Note{},
+		now:       func() time.Time { return time.Now().UTC().Truncate(time.Second) },
+		secret:    "6493a84f72d86e7de130",
+		token:     token,
+		user:      strings.SplitN(token, ":", 2)[0],
+	}
+	s.updateTime = s.now()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/posts/add", s.handlePostsAdd)
+	mux.HandleFunc("/posts/all", s.handlePostsAll)
+	mux.HandleFunc("/posts/dates", s.handlePostsDates)
+	mux.HandleFunc("/posts/delete", s.handlePostsDelete)
+	mux.HandleFunc("/posts/get", s.handlePostsGet)
+	mux.HandleFunc("/posts/recent", s.handlePostsRecent)
+	mux.HandleFunc("/posts/suggest", s.handlePostsSuggest)
+	mux.HandleFunc("/posts/update", s.handlePostsUpdate)
+	mux.HandleFunc("/tags/delete", s.handleTagsDelete)
+	mux.HandleFunc("/tags/get", s.handleTagsGet)
+	mux.HandleFunc("/tags/rename", s.handleTagsRename)
+	mux.HandleFunc("/user/secret", s.handleUserSecret)
+	mux.HandleFunc("/notes/list", s.handleNotesList)
+	mux.HandleFunc("/notes/", s.handleNotesById)
+
+	s.Server = httptest.NewServer(s.middleware(mux))
+	return s
+}
+
+// Client returns a thumbtack client for the server.
+// opts are applied after the endpoint and token options.
+func (s *Server) Client(opts ...thumbtack.Option) (*thumbtack.Client, error) {
+	token := s.token
+	opts = append([]thumbtack.Option{
+		thumbtack.WithEndpoint(s.Endpoint()),
+		thumbtack.WithToken(&token),
+	}, opts...)
+	return thumbtack.New(opts...)
+}
+
+// QuietClient returns a thumbtack client for the server that logs nothing.
+// opts are applied after the logger, and t fails if the client can not be created.
+func (s *Server) QuietClient(t testing.TB, opts ...thumbtack.Option) *thumbtack.Client {
+	t.Helper()
+	log := zerolog.Nop()
+	client, err := s.Client(append([]thumbtack.Option{thumbtack.WithLogger(&log)}, opts...)...)
+	if err != nil {
+		t.Fatalf("failed to create thumbtack client: %v", err)
+	}
+	return client
+}
+
+// Endpoint returns the url of the server, for thumbtack.WithEndpoint
+func (s *Server) Endpoint() *url.URL {
+	endpoint, _ := url.Parse(s.URL)
+	return endpoint
+}
+
+// AddBookmark stores bookmark as if it had been added with posts/add.
+// Hash and Meta are computed if empty, and Time defaults to now.
+func (s *Server) AddBookmark(bookmark thumbtack.Bookmark) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store(bookmark)
+}
+
+// AddNote stores note. Hash and Length are computed, and the times default to now.
+func (s *Server) AddNote(note thumbtack.Note) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if note.CreatedAt.IsZero() {
+		note.CreatedAt = s.now()
+	}
+	if note.UpdatedAt.IsZero() {
+		note.UpdatedAt = note.CreatedAt
+	}
+	note.Hash = hash(note.Text)[:20]
+	note.Length = float64(len(note.Text))
+	if note.Id == "" {
+		note.Id = hash(note.Title + note.CreatedAt.String())[:20]
+	}
+	s.notes[note.Id] = note
+}
+
+// Bookmarks returns the stored bookmarks, newest first
+func (s *Server) Bookmarks() []thumbtack.Bookmark {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sorted()
+}
+
+// FailNext makes the next requests fail with the given http status codes, in order
+func (s *Server) FailNext(statusCodes ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, statusCodes...)
+}
+
+// FailNextMatching makes the next request to path (e.g. /posts/add) whose query has param set to value
+// fail with the given http status code. Other requests are served as usual.
+func (s *Server) FailNextMatching(path string, param string, value string, statusCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.matchFailures = append(s.matchFailures, matchFailure{path: path, param: param, value: value, statusCode: statusCode})
+}
+
+// Requests returns the number of requests served so far
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// SetNow sets the clock used for new bookmarks, notes and the update time
+func (s *Server) SetNow(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// UpdateTime returns the time of the last change, as returned by posts/update
+func (s *Server) UpdateTime() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.updateTime
+}
+
+// middleware counts requests, injects failures and checks the auth token.
+// Every handler runs with the server locked.
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests++
+
+		if len(s.failures) > 0 {
+			code := s.failures[0]
+			s.failures = s.failures[1:]
+			http.Error(w, http.StatusText(code), code)
+			return
+		}
+
+		for i, failure := range s.matchFailures {
+			if r.URL.Path == failure.path && r.URL.Query().Get(failure.param) == failure.value {
+				s.matchFailures = append(s.matchFailures[:i], s.matchFailures[i+1:]...)
+				http.Error(w, http.StatusText(failure.statusCode), failure.statusCode)
+				return
+			}
+		}
+
+		if r.Method != http.MethodGet {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		if r.URL.Query().Get("auth_token") != s.token {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// store saves bookmark, filling in the computed fields, and advances the update time.
+// The server must be locked.
+func (s *Server) store(bookmark thumbtack.Bookmark) {
+	if bookmark.Time.IsZero() {
+		bookmark.Time = s.now()
+	}
+	bookmark.Time = bookmark.Time.UTC()
+	if bookmark.Tags == nil {
+		bookmark.Tags = []string{}
+	}
+	bookmark.Hash = hash(bookmark.Href)
+	bookmark.Meta = meta(bookmark)
+	s.bookmarks[bookmark.Href] = bookmark
+	s.touch()
+}
+
+// sorted returns the stored bookmarks, newest first. The server must be locked.
+func (s *Server) sorted() []thumbtack.Bookmark {
+	bookmarks := make([]thumbtack.Bookmark, 0, len(s.bookmarks))
+	for _, bookmark := range s.bookmarks {
+		bookmarks = append(bookmarks, bookmark)
+	}
+	sort.Slice(bookmarks, func(i, j int) bool {
+		if !bookmarks[i].Time.Equal(bookmarks[j].Time) {
+			return bookmarks[i].Time.After(bookmarks[j].Time)
+		}
+		return bookmarks[i].Href < bookmarks[j].Href
+	})
+	return bookmarks
+}
+
+// touch advances the update time. The server must be locked.
+func (s *Server) touch() {
+	now := s.now()
+	if !now.After(s.updateTime) {
+		now = s.updateTime.Add(time.Second)
+	}
+	s.updateTime = now
+}
+
+// hash returns the hexadecimal MD5 hash of value
+func hash(value string) string {
+	sum := md5.Sum([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// meta returns a change detection signature for bookmark
+func meta(bookmark thumbtack.Bookmark) string {
+	return hash(fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%t\x00%t\x00%s",
+		bookmark.Href,
+		bookmark.Description,
+		bookmark.Extended,
+		bookmark.Time.Format(time.RFC3339),
+		bookmark.Shared,
+		bookmark.ToRead,
+		strings.Join(bookmark.Tags, " "),
+	))
+}
+
+// splitTags splits a space separated list of tags, dropping empty ones
+func splitTags(tags string) []string {
+	return strings.Fields(tags)
+}
+
+// yesNo formats a boolean the way the Pinboard API does
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+// writeJSON writes value as the JSON response
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+// writeResultCode writes a posts/add or posts/delete style response
+func writeResultCode(w http.ResponseWriter, code string) {
+	writeJSON(w, map[string]string{"result_code": code})
+}
+
+// writeResult writes a tags/delete or tags/rename style response
+func writeResult(w http.ResponseWriter, result string) {
+	writeJSON(w, map[string]string{"result": result})
+}
@@ -0,0 +1,324 @@
+package thumbtacktest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rmrfslashbin/thumbtack"
+)
+
+// newTestClient returns a fake server and a client for it
+func newTestClient(t *testing.T) (*Server, *thumbtack.Client) {
+	srv := NewServer("test:abc123")
+	t.Cleanup(srv.Close)
+	return srv, srv.QuietClient(t)
+}
+
+// addBookmark adds a bookmark through the client
+func addBookmark(t *testing.T, client *thumbtack.Client, href string, title string, tags []string, timestamp time.Time) {
+	_, err := client.PostsAdd(&thumbtack.PostsAddInput{
+		Url:       &href,
+		Title:     &title,
+		Tags:      tags,
+		Timestamp: &timestamp,
+	})
+	if err != nil {
+		t.Fatalf("failed to add bookmark: %v", err)
+	}
+}
+
+// TestServerPostsAdd tests that posts/add stores bookmarks and returns real result codes
+func TestServerPostsAdd(t *testing.T) {
+	srv, client := newTestClient(t)
+
+	day := time.Date(2023, 3, 20, 16, 30, 35, 0, time.UTC)
+	addBookmark(t, client, "https://example.com", "Example", []string{"test", "example"}, day)
+
+	bookmarks := srv.Bookmarks()
+	if len(bookmarks) != 1 {
+		t.Fatalf("expected 1 bookmark, got %d", len(bookmarks))
+	}
+	if bookmarks[0].Description != "Example" || !bookmarks[0].Time.Equal(day) || !bookmarks[0].Shared {
+		t.Errorf("unexpected bookmark %+v", bookmarks[0])
+	}
+	if bookmarks[0].Hash != "c984d06aafbecf6bc55569f964148ea3" {
+		t.Errorf("expected hash to be the md5 of the url, got %s", bookmarks[0].Hash)
+	}
+
+	href := "https://example.com"
+	title := "Example"
+	replace := false
+	_, err := client.PostsAdd(&thumbtack.PostsAddInput{Url: &href, Title: &title, Replace: &replace})
+	if !errors.Is(err, thumbtack.ErrItemAlreadyExists) {
+		t.Errorf("expected ErrItemAlreadyExists, got %v", err)
+	}
+
+	empty := ""
+	_, err = client.PostsAdd(&thumbtack.PostsAddInput{Url: &empty, Title: &title})
+	if !errors.Is(err, thumbtack.ErrMissingURL) {
+		t.Errorf("expected ErrMissingURL, got %v", err)
+	}
+	_, err = client.PostsAdd(&thumbtack.PostsAddInput{Url: &href, Title: &empty})
+	if !errors.Is(err, thumbtack.ErrMustProvideTitle) {
+		t.Errorf("expected ErrMustProvideTitle, got %v", err)
+	}
+}
+
+// TestServerPostsGet tests filtering by date, tag and url
+func TestServerPostsGet(t *testing.T) {
+	_, client := newTestClient(t)
+
+	day1 := time.Date(2023, 3, 19, 10, 0, 0, 0, time.UTC)
+	day2 := time.Date(2023, 3, 20, 10, 0, 0, 0, time.UTC)
+	addBookmark(t, client, "https://example.com/1", "One", []string{"go"}, day1)
+	addBookmark(t, client, "https://example.com/2", "Two", []string{"go", "web"}, day2)
+	addBookmark(t, client, "https://example.com/3", "Three", []string{"web"}, day2.Add(time.Hour))
+
+	// the most recent day by default
+	posts, err := client.PostsGet(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(posts.Posts) != 2 || posts.User != "test" {
+		t.Errorf("expected 2 posts on the most recent day, got %d", len(posts.Posts))
+	}
+
+	posts, err = client.PostsGet(&thumbtack.PostsGetInput{Date: &day1})
+	if err != nil || len(posts.Posts) != 1 || posts.Posts[0].Href != "https://example.com/1" {
+		t.Errorf("expected the bookmark of day1, got %+v, %v", posts, err)
+	}
+
+	posts, err = client.PostsGet(&thumbtack.PostsGetInput{Date: &day2, Tags: []string{"go"}})
+	if err != nil || len(posts.Posts) != 1 || posts.Posts[0].Href != "https://example.com/2" {
+		t.Errorf("expected the go bookmark of day2, got %+v, %v", posts, err)
+	}
+
+	href := "https://example.com/3"
+	posts, err = client.PostsGet(&thumbtack.PostsGetInput{URL: &href})
+	if err != nil || len(posts.Posts) != 1 || posts.Posts[0].Description != "Three" {
+		t.Errorf("expected the bookmark for the url, got %+v, %v", posts, err)
+	}
+}
+
+// TestServerPostsAll tests filtering and paging posts/all
+func TestServerPostsAll(t *testing.T) {
+	_, client := newTestClient(t)
+
+	start := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		tags := []string{"all"}
+		if i%2 == 0 {
+			tags = append(tags, "even")
+		}
+		addBookmark(t, client, "https://example.com/"+string(rune('a'+i)), "Bookmark", tags, start.Add(time.Duration(i)*24*time.Hour))
+	}
+
+	bookmarks, err := client.PostsAll(nil)
+	if err != nil || len(*bookmarks) != 10 {
+		t.Fatalf("expected 10 bookmarks, got %v", err)
+	}
+	if (*bookmarks)[0].Href != "https://example.com/j" {
+		t.Errorf("expected newest first, got %s", (*bookmarks)[0].Href)
+	}
+	if (*bookmarks)[0].Meta == "" {
+		t.Errorf("expected meta to be included by default")
+	}
+
+	bookmarks, _ = client.PostsAll(&thumbtack.PostsAllInput{Tags: []string{"even"}})
+	if len(*bookmarks) != 5 {
+		t.Errorf("expected 5 even bookmarks, got %d", len(*bookmarks))
+	}
+
+	from := start.Add(5 * 24 * time.Hour)
+	bookmarks, _ = client.PostsAll(&thumbtack.PostsAllInput{FromDT: &from})
+	if len(*bookmarks) != 5 {
+		t.Errorf("expected 5 bookmarks from day 5, got %d", len(*bookmarks))
+	}
+
+	offset, results := 8, 5
+	bookmarks, _ = client.PostsAll(&thumbtack.PostsAllInput{Start: &offset, Results: &results})
+	if len(*bookmarks) != 2 {
+		t.Errorf("expected the last 2 bookmarks, got %d", len(*bookmarks))
+	}
+
+	paginator := client.NewPostsAllPaginator(nil, thumbtack.WithPageSize(3), thumbtack.WithPageInterval(0))
+	total := 0
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		total += len(page)
+	}
+	if total != 10 {
+		t.Errorf("expected the paginator to walk 10 bookmarks, got %d", total)
+	}
+}
+
+// TestServerPostsUpdate tests that posts/update advances with changes
+func TestServerPostsUpdate(t *testing.T) {
+	_, client := newTestClient(t)
+
+	before, err := client.PostsUpdate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	addBookmark(t, client, "https://example.com", "Example", nil, time.Now())
+	after, err := client.PostsUpdate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !after.UpdateTime.After(before.UpdateTime) {
+		t.Errorf("expected update time to advance, got %s then %s", before.UpdateTime, after.UpdateTime)
+	}
+
+	unchanged, _ := client.PostsUpdate()
+	if !unchanged.UpdateTime.Equal(after.UpdateTime) {
+		t.Errorf("expected update time to stay the same without changes")
+	}
+}
+
+// TestServerPostsDelete tests deleting bookmarks
+func TestServerPostsDelete(t *testing.T) {
+	srv, client := newTestClient(t)
+	addBookmark(t, client, "https://example.com", "Example", nil, time.Now())
+
+	if _, err := client.PostsDelete("https://example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(srv.Bookmarks()) != 0 {
+		t.Errorf("expected the bookmark to be deleted")
+	}
+	_, err := client.PostsDelete("https://example.com")
+	if !errors.Is(err, thumbtack.ErrItemNotFound) {
+		t.Errorf("expected ErrItemNotFound, got %v", err)
+	}
+}
+
+// TestServerPostsRecentDatesSuggest tests posts/recent, posts/dates and posts/suggest
+func TestServerPostsRecentDatesSuggest(t *testing.T) {
+	_, client := newTestClient(t)
+
+	day := time.Date(2023, 3, 20, 10, 0, 0, 0, time.UTC)
+	addBookmark(t, client, "https://example.com/1", "One", []string{"go"}, day)
+	addBookmark(t, client, "https://example.com/2", "Two", []string{"web"}, day.Add(time.Hour))
+	addBookmark(t, client, "https://other.com/", "Three", []string{"other"}, day.Add(48*time.Hour))
+
+	count := 2
+	recent, err := client.PostsRecent(&thumbtack.PostsRecentInput{Count: &count})
+	if err != nil || len(recent.Posts) != 2 || recent.Posts[0].Href != "https://other.com/" {
+		t.Errorf("expected the 2 most recent bookmarks, got %+v, %v", recent, err)
+	}
+
+	dates, err := client.PostsDates(nil)
+	if err != nil || dates.Dates["2023-03-20"] != 2 || dates.Dates["2023-03-22"] != 1 {
+		t.Errorf("unexpected dates %+v, %v", dates, err)
+	}
+
+	suggestions, err := client.PostsSuggest("https://example.com/3")
+	if err != nil || len(suggestions.Recommended) != 2 {
+		t.Errorf("expected the tags of the same host to be recommended, got %+v, %v", suggestions, err)
+	}
+}
+
+// TestServerTags tests tags/get, tags/rename and tags/delete
+func TestServerTags(t *testing.T) {
+	srv, client := newTestClient(t)
+
+	addBookmark(t, client, "https://example.com/1", "One", []string{"golang", "web"}, time.Now())
+	addBookmark(t, client, "https://example.com/2", "Two", []string{"golang", "go"}, time.Now())
+
+	tags, err := client.TagsGet()
+	if err != nil || tags.Tags["golang"] != 2 || tags.Count != 3 {
+		t.Fatalf("unexpected tags %+v, %v", tags, err)
+	}
+
+	old, new := "golang", "go"
+	if _, err := client.TagsRename(&thumbtack.TagsRenameInput{Old: &old, New: &new}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tags, _ = client.TagsGet()
+	if tags.Tags["go"] != 2 || tags.Tags["golang"] != 0 {
+		t.Errorf("expected golang to be merged into go, got %+v", tags.Tags)
+	}
+	for _, bookmark := range srv.Bookmarks() {
+		if bookmark.Href == "https://example.com/2" && len(bookmark.Tags) != 1 {
+			t.Errorf("expected duplicate tags to be merged, got %v", bookmark.Tags)
+		}
+	}
+
+	if _, err := client.TagsDelete("web"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tags, _ = client.TagsGet()
+	if _, ok := tags.Tags["web"]; ok {
+		t.Errorf("expected web to be deleted, got %+v", tags.Tags)
+	}
+}
+
+// TestServerNotesAndUser tests notes/list, notes/ID and user/secret
+func TestServerNotesAndUser(t *testing.T) {
+	srv, client := newTestClient(t)
+	srv.AddNote(thumbtack.Note{Id: "note1", Title: "Test Note", Text: "This is my test note"})
+
+	notes, err := client.NotesList()
+	if err != nil || notes.Count != 1 || notes.Notes[0].Title != "Test Note" {
+		t.Fatalf("unexpected notes %+v, %v", notes, err)
+	}
+	note, err := client.NotesById("note1")
+	if err != nil || note.Text != "This is my test note" || note.Length != 20 {
+		t.Errorf("unexpected note %+v, %v", note, err)
+	}
+	if _, err := client.NotesById("missing"); thumbtack.StatusCodeOf(err) != http.StatusNotFound {
+		t.Errorf("expected 404 for a missing note, got %v", err)
+	}
+
+	secret, err := client.UserSecret()
+	if err != nil || secret.Result == "" {
+		t.Errorf("expected a secret, got %+v, %v", secret, err)
+	}
+}
+
+// TestServerAuthAndFailures tests token checks and injected failures
+func TestServerAuthAndFailures(t *testing.T) {
+	srv, client := newTestClient(t)
+
+	srv.FailNext(http.StatusTooManyRequests)
+	if _, err := client.TagsGet(); !errors.Is(err, thumbtack.ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+	if _, err := client.TagsGet(); err != nil {
+		t.Errorf("expected the failure to only apply once, got %v", err)
+	}
+
+	token := "test:wrong"
+	bad := srv.QuietClient(t, thumbtack.WithToken(&token))
+	if _, err := bad.TagsGet(); !errors.Is(err, thumbtack.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if srv.Requests() != 3 {
+		t.Errorf("expected 3 requests, got %d", srv.Requests())
+	}
+}
+
+// TestServerFailNextMatching tests that a matching failure applies once, to the matching request only
+func TestServerFailNextMatching(t *testing.T) {
+	srv, client := newTestClient(t)
+	day := time.Date(2023, 3, 20, 16, 30, 35, 0, time.UTC)
+
+	srv.FailNextMatching("/posts/add", "url", "https://example.com/b", http.StatusInternalServerError)
+	addBookmark(t, client, "https://example.com/a", "A", nil, day)
+
+	href, title := "https://example.com/b", "B"
+	if _, err := client.PostsAdd(&thumbtack.PostsAddInput{Url: &href, Title: &title}); err == nil {
+		t.Error("expected the matching request to fail")
+	}
+	addBookmark(t, client, href, title, nil, day)
+
+	if bookmarks := srv.Bookmarks(); len(bookmarks) != 2 {
+		t.Errorf("expected 2 bookmarks, got %+v", bookmarks)
+	}
+}
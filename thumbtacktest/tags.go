@@ -0,0 +1,74 @@
+package thumbtacktest
+
+import (
+	"net/http"
+	"strings"
+)
+
+// handleTagsDelete emulates tags/delete by removing the tag from every bookmark
+func (s *Server) handleTagsDelete(w http.ResponseWriter, r *http.Request) {
+	tag := r.URL.Query().Get("tag")
+	if tag == "" {
+		writeResult(w, "something went wrong")
+		return
+	}
+
+	for _, bookmark := range s.sorted() {
+		tags := []string{}
+		for _, t := range bookmark.Tags {
+			if !strings.EqualFold(t, tag) {
+				tags = append(tags, t)
+			}
+		}
+		if len(tags) != len(bookmark.Tags) {
+			bookmark.Tags = tags
+			s.store(bookmark)
+		}
+	}
+	writeResult(w, "done")
+}
+
+// handleTagsGet emulates tags/get
+func (s *Server) handleTagsGet(w http.ResponseWriter, r *http.Request) {
+	tags := map[string]int{}
+	for _, bookmark := range s.bookmarks {
+		for _, tag := range bookmark.Tags {
+			tags[tag]++
+		}
+	}
+	writeJSON(w, tags)
+}
+
+// handleTagsRename emulates tags/rename by rewriting the tag on every bookmark.
+// Renaming onto a tag the bookmark already has merges the two.
+func (s *Server) handleTagsRename(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	old := q.Get("old")
+	new := q.Get("new")
+	if old == "" || new == "" || strings.ContainsAny(new, " ,") {
+		writeResult(w, "something went wrong")
+		return
+	}
+
+	for _, bookmark := range s.sorted() {
+		changed := false
+		seen := map[string]bool{}
+		tags := []string{}
+		for _, t := range bookmark.Tags {
+			if strings.EqualFold(t, old) {
+				t = new
+				changed = true
+			}
+			if seen[strings.ToLower(t)] {
+				continue
+			}
+			seen[strings.ToLower(t)] = true
+			tags = append(tags, t)
+		}
+		if changed {
+			bookmark.Tags = tags
+			s.store(bookmark)
+		}
+	}
+	writeResult(w, "done")
+}
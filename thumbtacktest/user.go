@@ -0,0 +1,8 @@
+package thumbtacktest
+
+import "net/http"
+
+// handleUserSecret emulates user/secret
+func (s *Server) handleUserSecret(w http.ResponseWriter, r *http.Request) {
+	writeResult(w, s.secret)
+}
@@ -1,6 +1,7 @@
 package thumbtack
 
 import (
+	"context"
 	"encoding/json"
 	"net/url"
 )
@@ -8,6 +9,11 @@ import (
 // UserSecret Returns the user's secret RSS key.
 // https://pinboard.in/api/#user_secret
 func (c *Client) UserSecret() (*Result, error) {
+	return c.UserSecretWithContext(context.Background())
+}
+
+// UserSecretWithContext is the same as UserSecret, but uses ctx for cancellation and deadlines.
+func (c *Client) UserSecretWithContext(ctx context.Context) (*Result, error) {
 	// Set up the query parameters
 	v := url.Values{}
 	v.Set("format", c.format)
@@ -18,13 +24,13 @@ func (c *Client) UserSecret() (*Result, error) {
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.callEndpoint(userSecret, v.Encode())
+	body, err := c.callEndpoint(ctx, "UserSecret", userSecret, v.Encode())
 	if err != nil {
 		c.log.Error().
 			Str("function", "thumbtack::UserSecret").
 			Str("endpoint", c.endpoint.String()).
 			Str("path", userSecret).
-			Str("query", v.Encode()).
+			Str("query", c.redact(v.Encode())).
 			Msg("error calling endpoint")
 		return nil, err
 	}
@@ -35,6 +41,7 @@ func (c *Client) UserSecret() (*Result, error) {
 	if err != nil {
 		c.log.Error().Msg("error unmarshalling response")
 		return nil, &ErrUnmarshalResponse{
+			Api:  "UserSecret",
 			Body: *body,
 			Err:  err,
 		}